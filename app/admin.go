@@ -0,0 +1,97 @@
+package app
+
+import (
+	"html/template"
+	"net/http"
+	"slices"
+
+	"go.uber.org/zap"
+)
+
+// handle registers handler on endpoint, recording route for index page.
+//
+// Already registered patterns are skipped.
+func (e *httpEndpoint) handle(pattern string, h http.Handler) {
+	e.routesMux.Lock()
+	defer e.routesMux.Unlock()
+	if slices.Contains(e.routes, pattern) {
+		return
+	}
+	e.routes = append(e.routes, pattern)
+	e.mux.Handle(pattern, h)
+}
+
+func (e *httpEndpoint) listRoutes() []string {
+	e.routesMux.Lock()
+	defer e.routesMux.Unlock()
+	routes := slices.Clone(e.routes)
+	slices.Sort(routes)
+	return routes
+}
+
+var adminIndexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Admin</title></head>
+<body>
+<h1>Admin</h1>
+<ul>
+{{- range . }}
+<li><a href="{{ . }}">{{ . }}</a></li>
+{{- end }}
+</ul>
+</body>
+</html>
+`))
+
+// indexHandler returns handler that lists mounted routes.
+func (e *httpEndpoint) indexHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var routes []string
+		for _, route := range e.listRoutes() {
+			if route == "/{$}" {
+				continue
+			}
+			routes = append(routes, route)
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = adminIndexTemplate.Execute(w, routes)
+	})
+}
+
+// mount registers built-in handler on endpoint e (if not nil) and admin endpoint.
+func (m *Telemetry) mount(e *httpEndpoint, pattern string, h http.Handler) {
+	if e != nil {
+		e.handle(pattern, h)
+	}
+	if m.admin != nil {
+		m.admin.handle(pattern, h)
+	}
+}
+
+// adminEndpoint returns endpoint for user handlers: ADMIN_ADDR endpoint or,
+// if not set, PPROF_ADDR endpoint.
+func (m *Telemetry) adminEndpoint() *httpEndpoint {
+	if m.admin != nil {
+		return m.admin
+	}
+	for _, e := range m.http {
+		if slices.Contains(e.services, "pprof") {
+			return e
+		}
+	}
+	return nil
+}
+
+// HandleAdmin registers handler for pattern on admin HTTP server, see [http.ServeMux] for
+// pattern syntax.
+//
+// Admin server listens on ADMIN_ADDR, falling back to PPROF_ADDR. If neither is set,
+// handler is not registered.
+func (m *Telemetry) HandleAdmin(pattern string, h http.Handler) {
+	e := m.adminEndpoint()
+	if e == nil {
+		m.lg.Debug("Admin server is not configured, skipping handler", zap.String("pattern", pattern))
+		return
+	}
+	e.handle(pattern, h)
+}
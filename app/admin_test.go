@@ -0,0 +1,113 @@
+package app
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestTelemetry_HandleAdmin(t *testing.T) {
+	t.Run("NotConfigured", func(t *testing.T) {
+		m := &Telemetry{lg: zap.NewNop()}
+		m.HandleAdmin("/debug/custom", http.NotFoundHandler())
+		require.Empty(t, m.http)
+	})
+	t.Run("FallbackToPprof", func(t *testing.T) {
+		m := &Telemetry{lg: zap.NewNop()}
+		e := m.registerEndpoint("localhost:8081", "pprof")
+		m.HandleAdmin("/debug/custom", http.NotFoundHandler())
+		require.Equal(t, []string{"/debug/custom"}, e.listRoutes())
+	})
+	t.Run("AdminAddr", func(t *testing.T) {
+		setupTestEnv(t)
+		t.Setenv("OTEL_METRICS_EXPORTER", "prometheus")
+		t.Setenv("ADMIN_ADDR", "localhost:0")
+		t.Setenv("PPROF_ADDR", "localhost:1")
+		t.Setenv("TRACEZ", "true")
+
+		a, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+			return nil
+		}, testOptions()...)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, a.Stop(context.Background()))
+		})
+
+		m := a.Telemetry()
+		m.HandleAdmin("/debug/custom", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, "custom")
+		}))
+		require.NotNil(t, m.admin)
+		require.Equal(t, []string{
+			"/debug/custom",
+			"/debug/loglevel",
+			"/debug/pprof/",
+			"/debug/reload",
+			"/debug/tracez",
+			"/healthz",
+			"/livez",
+			"/metrics",
+			"/readyz",
+			"/{$}",
+		}, m.admin.listRoutes())
+
+		s := httptest.NewServer(m.admin.mux)
+		t.Cleanup(s.Close)
+		get := func(path string) (int, string) {
+			res, err := s.Client().Get(s.URL + path)
+			require.NoError(t, err)
+			defer func() { _ = res.Body.Close() }()
+			data, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+			return res.StatusCode, string(data)
+		}
+
+		code, body := get("/")
+		require.Equal(t, http.StatusOK, code)
+		require.Contains(t, body, `<a href="/debug/custom">`)
+		require.Contains(t, body, `<a href="/metrics">`)
+		require.NotContains(t, body, `{$}`)
+
+		code, body = get("/debug/custom")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "custom", body)
+
+		code, _ = get("/metrics")
+		require.Equal(t, http.StatusOK, code)
+
+		// Spans are recorded even with "none" exporter.
+		_, span := m.TracerProvider().Tracer("test").Start(context.Background(), "tracez-span")
+		span.End()
+		code, body = get("/debug/tracez")
+		require.Equal(t, http.StatusOK, code)
+		require.Contains(t, body, "tracez-span")
+
+		code, _ = get("/unknown")
+		require.Equal(t, http.StatusNotFound, code)
+	})
+	t.Run("WithoutTracez", func(t *testing.T) {
+		setupTestEnv(t)
+		t.Setenv("ADMIN_ADDR", "localhost:0")
+
+		a, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+			return nil
+		}, testOptions()...)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, a.Stop(context.Background()))
+		})
+
+		// Exposing admin server does not make spans recorded.
+		m := a.Telemetry()
+		require.Nil(t, m.tracez)
+		require.NotContains(t, m.admin.listRoutes(), "/debug/tracez")
+		_, span := m.TracerProvider().Tracer("test").Start(context.Background(), "span")
+		require.False(t, span.IsRecording())
+		span.End()
+	})
+}
@@ -5,17 +5,15 @@ package app
 import (
 	"context"
 	"fmt"
-	"log/slog"
 	"os"
 	"os/signal"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/KimMachineGun/automemlimit/memlimit"
 	"github.com/go-faster/errors"
-	slogzap "github.com/samber/slog-zap/v2"
 	"go.opentelemetry.io/otel/sdk/resource"
-	"go.uber.org/automaxprocs/maxprocs"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"golang.org/x/sync/errgroup"
@@ -24,33 +22,78 @@ import (
 
 	"github.com/go-faster/sdk/autologs"
 	"github.com/go-faster/sdk/cliversion"
+	"github.com/go-faster/sdk/otelconfig"
 	"github.com/go-faster/sdk/zctx"
 )
 
 const (
-	exitCodeOk             = 0
-	exitCodeApplicationErr = 1
-	exitCodeWatchdog       = 1
+	defaultShutdownTimeout = time.Second * 5
+	defaultWatchdogTimeout = defaultShutdownTimeout + time.Second*5
 )
 
-const (
-	shutdownTimeout = time.Second * 5
-	watchdogTimeout = shutdownTimeout + time.Second*5
+var (
+	// ErrWatchdog is returned by [App.Wait] if application did not stop
+	// during shutdown and watchdog timeouts.
+	ErrWatchdog = errors.New("graceful shutdown watchdog triggered")
+	// ErrForcedShutdown is returned by [App.Wait] if second shutdown signal
+	// is received during graceful shutdown.
+	ErrForcedShutdown = errors.New("forced shutdown by signal")
+	// ErrPanic is returned by [App.Wait] if application panicked.
+	ErrPanic = errors.New("panic")
 )
 
-// Run f until interrupt.
+// RunFunc is application entrypoint.
 //
-// If errors.Is(err, ctx.Err()) is valid for returned error, shutdown is considered graceful.
-// Context is cancelled on SIGINT. After watchdogTimeout application is forcefully terminated
-// with exitCodeWatchdog.
-func Run(f func(ctx context.Context, lg *zap.Logger, t *Telemetry) error, op ...Option) {
-	// Apply options.
+// The ctx is [Telemetry.ShutdownContext], lg is logger with OpenTelemetry bridge.
+type RunFunc func(ctx context.Context, lg *zap.Logger, t *Telemetry) error
+
+// App is an application with telemetry and graceful shutdown.
+//
+// Unlike [Run], App never calls os.Exit, so it can be used in tests
+// or embedded in another process. Use [WithoutGlobalState] to not
+// modify global OpenTelemetry providers.
+type App struct {
+	f    RunFunc
+	op   []Option
+	opts options
+	lg   *zap.Logger
+	t    *Telemetry
+
+	baseCtx        context.Context
+	baseCancel     context.CancelFunc
+	shutdownCancel context.CancelFunc
+
+	leaks   *leakChecker
+	limits  *runtimeLimits // nil without global state
+	startup *startup
+
+	startOnce sync.Once
+	drainOnce sync.Once
+	done      chan struct{}
+	doneOnce  sync.Once
+	err       error
+}
+
+// buildOptions applies default values, environment variables and options.
+func buildOptions(op []Option) (options, error) {
 	opts := options{
 		zapConfig:       zap.NewProductionConfig(),
 		zapTee:          true,
 		otelZap:         true,
 		ctx:             context.Background(),
 		resourceOptions: defaultResourceOptions(),
+		signals:         defaultSignals(),
+		reloadSignals:   defaultReloadSignals(),
+		shutdownTimeout: defaultShutdownTimeout,
+		watchdogTimeout: defaultWatchdogTimeout,
+		globalState:     true,
+
+		maxProcs:         true,
+		minProcs:         1,
+		memLimit:         true,
+		memLimitRatio:    defaultMemLimitRatio,
+		memLimitProvider: memlimit.FromCgroup,
+		limitsRefresh:    defaultLimitsRefresh,
 	}
 	opts.resourceFn = func(ctx context.Context) (*resource.Resource, error) {
 		r, err := resource.New(ctx, opts.resourceOptions...)
@@ -63,22 +106,91 @@ func Run(f func(ctx context.Context, lg *zap.Logger, t *Telemetry) error, op ...
 		// Override default.
 		opts.zapTee = v
 	}
+	if v := os.Getenv("SHUTDOWN_SIGNALS"); v != "" {
+		signals, err := parseSignals(v)
+		if err != nil {
+			return opts, errors.Wrap(err, "parse SHUTDOWN_SIGNALS")
+		}
+		opts.signals = signals
+	}
+	if v := os.Getenv("RELOAD_SIGNALS"); v != "" {
+		signals, err := parseSignals(v)
+		if err != nil {
+			return opts, errors.Wrap(err, "parse RELOAD_SIGNALS")
+		}
+		opts.reloadSignals = signals
+	}
+	if v := os.Getenv("SHUTDOWN_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return opts, errors.Wrap(err, "parse SHUTDOWN_TIMEOUT")
+		}
+		opts.shutdownTimeout = d
+	}
+	if v := os.Getenv("SHUTDOWN_DRAIN_DELAY"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return opts, errors.Wrap(err, "parse SHUTDOWN_DRAIN_DELAY")
+		}
+		opts.drainDelay = d
+	}
+	if v, err := strconv.ParseBool(os.Getenv("GOROUTINE_LEAK_CHECK")); err == nil {
+		opts.leakCheck = v
+	}
+	if v := os.Getenv("WATCHDOG_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return opts, errors.Wrap(err, "parse WATCHDOG_TIMEOUT")
+		}
+		opts.watchdogTimeout = d
+	}
+	if v, err := strconv.ParseBool(os.Getenv("AUTOMAXPROCS")); err == nil {
+		opts.maxProcs = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("AUTOMAXPROCS_MIN")); err == nil {
+		opts.minProcs = v
+	}
+	switch v := os.Getenv("AUTOMEMLIMIT"); v {
+	case "":
+	case "off":
+		opts.memLimit = false
+	default:
+		ratio, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return opts, errors.Wrap(err, "parse AUTOMEMLIMIT")
+		}
+		opts.memLimitRatio = ratio
+	}
+	if v := os.Getenv("AUTOMEMLIMIT_PROVIDER"); v != "" {
+		p, err := memLimitProvider(v)
+		if err != nil {
+			return opts, errors.Wrap(err, "parse AUTOMEMLIMIT_PROVIDER")
+		}
+		opts.memLimitProvider = p
+	}
+	if v := os.Getenv("LIMITS_REFRESH_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return opts, errors.Wrap(err, "parse LIMITS_REFRESH_INTERVAL")
+		}
+		opts.limitsRefresh = d
+	}
 	for _, o := range op {
 		o.apply(&opts)
 	}
-
-	ctx := opts.ctx
-	if opts.otelZap {
-		ctx = zctx.WithOpenTelemetryZap(ctx)
+	if opts.config == nil {
+		c, err := otelconfig.FromEnv()
+		if err != nil {
+			return opts, errors.Wrap(err, "load OTEL_CONFIG_FILE")
+		}
+		opts.config = c
 	}
-	ctx, baseCtxCancel := context.WithCancel(ctx)
-	defer baseCtxCancel()
 
 	// Setup logger.
 	if s := os.Getenv("OTEL_LOG_LEVEL"); s != "" {
 		var lvl zapcore.Level
 		if err := lvl.UnmarshalText([]byte(s)); err != nil {
-			panic(err)
+			return opts, errors.Wrap(err, "parse OTEL_LOG_LEVEL")
 		}
 		opts.modifyZapConfig(func(c *zap.Config) {
 			c.Level.SetLevel(lvl)
@@ -89,85 +201,253 @@ func Run(f func(ctx context.Context, lg *zap.Logger, t *Telemetry) error, op ...
 			c.EncoderConfig.NewReflectedEncoder = zapencoder.NewReflectedEncoder
 		})
 	}
+	return opts, nil
+}
 
-	lg := opts.buildLogger()
-	defer func() { _ = lg.Sync() }()
+// New initializes logger and telemetry for application f.
+//
+// Application is not started until [App.Start] is called.
+func New(f RunFunc, op ...Option) (*App, error) {
+	st := newStartup(time.Now())
+	opts, err := buildOptions(op)
+	if err != nil {
+		return nil, errors.Wrap(err, "options")
+	}
+
+	ctx := opts.ctx
+	if opts.otelZap {
+		ctx = zctx.WithOpenTelemetryZap(ctx)
+	}
+	ctx, baseCtxCancel := context.WithCancel(ctx)
+
+	lg, err := opts.buildLogger()
+	if err != nil {
+		baseCtxCancel()
+		return nil, errors.Wrap(err, "build logger")
+	}
 	// Add logger to root context.
 	ctx = zctx.Base(ctx, lg)
 
 	// Explicit context for graceful shutdown.
-	shutdownCtx, cancel := signal.NotifyContext(ctx, os.Interrupt)
-	defer cancel()
+	shutdownCtx, cancel := context.WithCancel(ctx)
 
+	a := &App{
+		f:    f,
+		op:   op,
+		opts: opts,
+		lg:   lg,
+
+		baseCancel:     baseCtxCancel,
+		shutdownCancel: cancel,
+		startup:        st,
+		done:           make(chan struct{}),
+	}
+	if err := a.init(ctx, shutdownCtx); err != nil {
+		cancel()
+		baseCtxCancel()
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *App) init(ctx, shutdownCtx context.Context) error {
+	var (
+		opts = a.opts
+		lg   = a.lg
+	)
+	var (
+		info    cliversion.Info
+		hasInfo bool
+	)
 	if opts.modulePath != "" {
-		if info, ok := cliversion.GetInfo(opts.modulePath); ok {
-			lg.Info("Starting",
-				zap.String("version", info.Version),
-				zap.String("commit", info.Commit),
-				zap.String("go_version", info.GoVersion),
-				zap.Bool("modified", info.Modified),
-			)
-		} else {
-			lg.Info("Starting")
-		}
+		info, hasInfo = cliversion.GetInfo(opts.modulePath)
+	}
+	if hasInfo {
+		lg.Info("Starting",
+			zap.String("version", info.Version),
+			zap.String("commit", info.Commit),
+			zap.String("go_version", info.GoVersion),
+			zap.Bool("modified", info.Modified),
+		)
 	} else {
 		lg.Info("Starting")
 	}
-	res, err := opts.resourceFn(ctx)
+	done := a.startup.phase("resource")
+	res, err := newResource(ctx, opts, info, hasInfo)
+	done(err)
 	if err != nil {
-		panic(fmt.Sprintf("failed to get resource: %v", err))
+		return err
 	}
 
+	done = a.startup.phase("telemetry")
 	m, err := newTelemetry(
 		ctx, shutdownCtx,
 		lg.Named("metrics"),
 		res,
-		opts.meterOptions, opts.tracerOptions, opts.loggerOptions,
+		opts,
+		a.startup,
 	)
+	done(err)
 	if err != nil {
-		panic(err)
+		return errors.Wrap(err, "telemetry")
+	}
+	m.reload = func(ctx context.Context) (options, *resource.Resource, error) {
+		opts, err := buildOptions(a.op)
+		if err != nil {
+			return opts, nil, errors.Wrap(err, "options")
+		}
+		res, err := newResource(ctx, opts, info, hasInfo)
+		return opts, res, err
+	}
+	if hasInfo {
+		if err := m.registerBuildInfo(info); err != nil {
+			return errors.Wrap(err, "build info metric")
+		}
+	}
+	if err := m.registerDrain(); err != nil {
+		return errors.Wrap(err, "lame duck metric")
+	}
+	if err := m.registerStartup(); err != nil {
+		return errors.Wrap(err, "startup metric")
 	}
 
 	// Setup logs.
 	if ctx, err = autologs.Setup(ctx, m.LoggerProvider(), opts.zapTee); err != nil {
-		panic(fmt.Sprintf("failed to setup logs: %v", err))
+		return errors.Wrap(err, "setup logs")
 	}
 
-	shutdownCtx = zctx.Base(shutdownCtx, zctx.From(ctx))
-	m.shutdownContext = shutdownCtx
+	m.shutdownContext = zctx.Base(shutdownCtx, zctx.From(ctx))
 	m.baseContext = ctx
+	m.shutdownTimeout = opts.shutdownTimeout
+	a.t = m
+	a.baseCtx = ctx
 
-	{
-		// Automatically setting GOMAXPROCS.
-		set := true // enabled by default
-		if v, err := strconv.ParseBool(os.Getenv("AUTOMAXPROCS")); err == nil {
-			set = v
-		}
-		minProcs := 1
-		if v, err := strconv.Atoi(os.Getenv("AUTOMAXPROCS_MIN")); err == nil {
-			minProcs = v
-		}
-		if set {
-			if _, err := maxprocs.Set(
-				maxprocs.Logger(lg.Sugar().Infof),
-				maxprocs.Min(minProcs),
-			); err != nil {
-				lg.Warn("Failed to set GOMAXPROCS", zap.Error(err))
-			}
+	if !opts.globalState {
+		return nil
+	}
+	// Automatically setting GOMAXPROCS and GOMEMLIMIT.
+	// https://github.com/uber-go/automaxprocs
+	// https://github.com/KimMachineGun/automemlimit
+	// https://tip.golang.org/doc/gc-guide#Memory_limit
+	done = a.startup.phase("limits")
+	a.limits = newRuntimeLimits(lg, opts)
+	a.limits.apply(true)
+	done(nil)
+	if err := a.limits.register(m.MeterProvider()); err != nil {
+		return errors.Wrap(err, "runtime limits metrics")
+	}
+	return nil
+}
+
+// newResource returns application resource from options, configuration file
+// and build info.
+func newResource(ctx context.Context, opts options, info cliversion.Info, hasInfo bool) (*resource.Resource, error) {
+	res, err := opts.resourceFn(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "get resource")
+	}
+	if c := opts.config; c != nil {
+		if res, err = c.MergeResource(ctx, res); err != nil {
+			return nil, errors.Wrap(err, "config resource")
 		}
 	}
-	{
-		// Automatically set GOMEMLIMIT.
-		// https://github.com/KimMachineGun/automemlimit
-		// https://tip.golang.org/doc/gc-guide#Memory_limit
-		logger := slog.New(slogzap.Option{Level: slog.LevelDebug, Logger: lg}.NewZapHandler())
-		if _, err := memlimit.SetGoMemLimitWithOpts(memlimit.WithLogger(logger)); err != nil {
-			lg.Warn("Failed to set memory limit", zap.Error(err))
+	if hasInfo {
+		// Explicit resource attributes take precedence over build info.
+		if res, err = resource.Merge(buildInfoResource(info), res); err != nil {
+			return nil, errors.Wrap(err, "merge build info resource")
 		}
 	}
+	return res, nil
+}
 
-	g, ctx := errgroup.WithContext(ctx)
+// Telemetry returns application telemetry.
+func (a *App) Telemetry() *Telemetry {
+	return a.t
+}
+
+// Logger returns application logger.
+func (a *App) Logger() *zap.Logger {
+	return a.lg
+}
+
+// Done returns channel that is closed when application is stopped.
+func (a *App) Done() <-chan struct{} {
+	return a.done
+}
+
+// finish records application result and releases resources.
+//
+// Only first call has effect.
+func (a *App) finish(err error) {
+	a.doneOnce.Do(func() {
+		a.err = err
+		a.shutdownCancel()
+		a.baseCancel()
+		if a.t != nil {
+			_ = a.t.sd.close()
+		}
+		close(a.done)
+	})
+}
+
+// Start starts application and telemetry servers in background.
+//
+// Use [App.Wait] to wait for application to stop and [App.Stop] to
+// trigger graceful shutdown.
+func (a *App) Start(ctx context.Context) error {
+	var started bool
+	a.startOnce.Do(func() {
+		started = true
+	})
+	if !started {
+		return errors.New("already started")
+	}
+	if err := ctx.Err(); err != nil {
+		a.finish(err)
+		return errors.Wrap(err, "start")
+	}
+
+	var (
+		lg = a.lg
+		m  = a.t
+		f  = a.f
+	)
+	if a.opts.leakCheck {
+		a.leaks = newLeakChecker()
+	}
+	done := a.startup.phase("start")
+	err := m.start(ctx)
+	done(err)
+	if err != nil {
+		// Releasing telemetry.
+		m.shutdown(context.WithoutCancel(ctx))
+		a.finish(err)
+		return err
+	}
+	a.handleSignals()
+	a.handleReload()
+	a.refreshLimits()
+	a.handleSDNotify()
+	a.startup.finish(a.baseCtx, m.TracerProvider())
+
+	// Telemetry is flushed only after application function returns.
+	appDone := make(chan struct{})
+	m.OnStop(PhaseApplication, "application", func(ctx context.Context) error {
+		select {
+		case <-appDone:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	m.OnStop(PhaseApplication, "workers", m.workers.wait)
+
+	g, ctx := errgroup.WithContext(a.baseCtx)
+	m.baseContext = ctx
+	m.workers.start(g)
 	g.Go(func() (rerr error) {
+		defer close(appDone)
 		defer lg.Info("Shutting down")
 		defer func() {
 			// Recovering panic to allow telemetry to flush.
@@ -176,10 +456,9 @@ func Run(f func(ctx context.Context, lg *zap.Logger, t *Telemetry) error, op ...
 					zap.String("panic", fmt.Sprintf("%v", ec)),
 					zap.StackSkip("stack", 1),
 				)
-				rerr = fmt.Errorf("shutting down (panic): %v", ec)
+				rerr = errors.Wrap(panicError(ec), "shutting down")
 			}
 		}()
-		m.baseContext = ctx
 		if err := f(m.shutdownContext, zctx.From(ctx), m); err != nil {
 			if errors.Is(err, m.shutdownContext.Err()) {
 				// Parent context got cancelled, error is expected.
@@ -190,7 +469,7 @@ func Run(f func(ctx context.Context, lg *zap.Logger, t *Telemetry) error, op ...
 		}
 
 		// Also shutting down metrics server to stop error group.
-		cancel()
+		a.shutdownCancel()
 
 		return nil
 	})
@@ -201,32 +480,175 @@ func Run(f func(ctx context.Context, lg *zap.Logger, t *Telemetry) error, op ...
 		}
 		return nil
 	})
-
 	go func() {
-		// Guaranteed way to kill application.
-		// Helps if f is stuck, e.g. deadlock during shutdown.
-		<-shutdownCtx.Done()
-		lg.Info("Shutdown triggered. Waiting for graceful shutdown")
-		time.Sleep(shutdownTimeout)
-		baseCtxCancel()
+		// Failure of any group member triggers graceful shutdown of others.
+		select {
+		case <-ctx.Done():
+			a.shutdownCancel()
+		case <-a.done:
+		}
+	}()
+	go func() {
+		err := g.Wait()
+		m.workers.close()
+		a.reportLeaks()
+		a.finish(err)
+	}()
+	go a.watchdog()
 
-		// Context is canceled, giving application time to shut down gracefully.
+	return nil
+}
+
+// watchdog is a guaranteed way to stop application.
+//
+// Helps if f is stuck, e.g. deadlock during shutdown.
+func (a *App) watchdog() {
+	lg := a.lg
+	select {
+	case <-a.t.shutdownContext.Done():
+	case <-a.done:
+		return
+	}
+	lg.Info("Shutdown triggered. Waiting for graceful shutdown")
+	select {
+	case <-time.After(a.opts.shutdownTimeout):
+	case <-a.done:
+		return
+	}
+	a.baseCancel()
+
+	// Context is canceled, giving application time to shut down gracefully.
+
+	lg.Info("Base context cancelled. Forcing shutdown")
+	select {
+	case <-time.After(a.opts.watchdogTimeout):
+	case <-a.done:
+		return
+	}
+
+	// Application is not shutting down gracefully, abandon it.
+
+	lg.Warn("Graceful shutdown watchdog triggered: forcing hard shutdown")
+	a.finish(ErrWatchdog)
+}
 
-		lg.Info("Base context cancelled. Forcing shutdown")
-		time.Sleep(watchdogTimeout)
+// handleSignals triggers graceful shutdown on first signal and
+// forced shutdown on second one.
+func (a *App) handleSignals() {
+	if len(a.opts.signals) == 0 {
+		return
+	}
+	// Buffered to not miss second signal while shutting down.
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, a.opts.signals...)
 
-		// Application is not shutting down gracefully, kill it.
-		// This code should not be executed if f is already returned.
+	lg := a.lg
+	go func() {
+		defer signal.Stop(signals)
+		select {
+		case s := <-signals:
+			lg.Info("Got signal, shutting down", zap.Stringer("signal", s))
+			a.drain()
+		case <-a.t.shutdownContext.Done():
+		case <-a.done:
+			return
+		}
 
-		lg.Warn("Graceful shutdown watchdog triggered: forcing hard shutdown")
-		os.Exit(exitCodeWatchdog)
+		// Second signal forces shutdown, skipping graceful shutdown timeouts.
+		select {
+		case s := <-signals:
+			lg.Warn("Got second signal: forcing hard shutdown", zap.Stringer("signal", s))
+			a.finish(ErrForcedShutdown)
+		case <-a.done:
+		}
 	}()
+}
+
+// Wait waits for application to stop and returns its error.
+//
+// Returns [ErrWatchdog] or [ErrForcedShutdown] if application was
+// abandoned during shutdown.
+func (a *App) Wait() error {
+	<-a.done
+	return a.err
+}
+
+// Shutdown triggers graceful shutdown without waiting for it.
+//
+// If drain delay is set, application enters lame-duck mode first,
+// see [WithShutdownDrainDelay].
+func (a *App) Shutdown() {
+	a.drain()
+}
+
+// Stop triggers graceful shutdown and waits for application to stop
+// or ctx to be done.
+//
+// If application was not started, telemetry is shut down.
+func (a *App) Stop(ctx context.Context) error {
+	a.startOnce.Do(func() {
+		// Not started, only releasing telemetry.
+		a.t.shutdown(ctx)
+		a.finish(nil)
+	})
+	a.drain()
+	select {
+	case <-a.done:
+		return a.err
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "stop")
+	}
+}
 
-	if err := g.Wait(); err != nil {
-		lg.Error("Failed", zap.Error(err))
-		os.Exit(exitCodeApplicationErr)
+// Run f until interrupt.
+//
+// If errors.Is(err, ctx.Err()) is valid for returned error, shutdown is considered graceful.
+// Context is cancelled on SIGINT or SIGTERM, see [WithShutdownSignals], after
+// drain delay if set, see [WithShutdownDrainDelay].
+// After shutdown timeout base context is cancelled, and after watchdog timeout application
+// is forcefully terminated with exit code 3.
+//
+// Second shutdown signal terminates application immediately with exit code 2.
+// Application panic results in exit code 4, other errors in exit code 1,
+// unless exit code is set by [ExitCode].
+//
+// See [App] for lifecycle that does not call os.Exit.
+func Run(f func(ctx context.Context, lg *zap.Logger, t *Telemetry) error, op ...Option) {
+	a, err := New(f, op...)
+	if err != nil {
+		panic(err)
+	}
+	lg := a.Logger()
+	if err := a.Start(context.Background()); err != nil {
+		panic(err)
 	}
 
-	lg.Info("Application stopped")
-	os.Exit(exitCodeOk)
+	code := logExit(lg, a.Wait())
+	_ = lg.Sync()
+	os.Exit(code)
+}
+
+// logExit logs application error and returns process exit code for it.
+func logExit(lg *zap.Logger, err error) int {
+	code := exitCode(err)
+	switch {
+	case err == nil:
+		lg.Info("Application stopped")
+	case errors.Is(err, ErrWatchdog):
+		lg.Error("Application did not stop, dumping goroutines", zap.Int("exit_code", code))
+		_, _ = os.Stderr.Write(dumpGoroutines())
+	case errors.Is(err, ErrForcedShutdown):
+		lg.Warn("Application was forced to stop",
+			zap.Error(err),
+			zap.Strings("error_chain", errorChain(err)),
+			zap.Int("exit_code", code),
+		)
+	default:
+		lg.Error("Failed",
+			zap.Error(err),
+			zap.Strings("error_chain", errorChain(err)),
+			zap.Int("exit_code", code),
+		)
+	}
+	return code
 }
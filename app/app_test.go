@@ -0,0 +1,168 @@
+package app
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.27.0"
+	"go.uber.org/zap"
+
+	"github.com/go-faster/sdk/autotracer"
+)
+
+func setupTestEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("OTEL_TRACES_EXPORTER", "none")
+	t.Setenv("OTEL_METRICS_EXPORTER", "none")
+	t.Setenv("OTEL_LOGS_EXPORTER", "none")
+	t.Setenv("OTEL_LOG_LEVEL", "debug")
+}
+
+func testOptions(op ...Option) []Option {
+	return include([]Option{
+		WithoutGlobalState(),
+		WithShutdownSignals(),
+		WithZapConfig(zap.NewDevelopmentConfig()),
+	}, op...)
+}
+
+func TestApp(t *testing.T) {
+	t.Run("Stop", func(t *testing.T) {
+		setupTestEnv(t)
+		tracerProvider := otel.GetTracerProvider()
+
+		var (
+			started = make(chan struct{})
+			stopped = make(chan struct{})
+		)
+		a, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+			close(started)
+			<-ctx.Done()
+			close(stopped)
+			return ctx.Err()
+		}, testOptions()...)
+		require.NoError(t, err)
+		require.NotNil(t, a.Telemetry())
+		require.NoError(t, a.Start(context.Background()))
+		require.Error(t, a.Start(context.Background()), "should not start twice")
+
+		<-started
+		require.NoError(t, a.Stop(context.Background()))
+		<-stopped
+		require.NoError(t, a.Wait())
+
+		// Global state should be untouched.
+		require.Equal(t, tracerProvider, otel.GetTracerProvider())
+	})
+	t.Run("Return", func(t *testing.T) {
+		setupTestEnv(t)
+		a, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+			return nil
+		}, testOptions()...)
+		require.NoError(t, err)
+		require.NoError(t, a.Start(context.Background()))
+		require.NoError(t, a.Wait())
+	})
+	t.Run("Error", func(t *testing.T) {
+		setupTestEnv(t)
+		testErr := errors.New("test error")
+		a, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+			return testErr
+		}, testOptions()...)
+		require.NoError(t, err)
+		require.NoError(t, a.Start(context.Background()))
+		require.ErrorIs(t, a.Wait(), testErr)
+	})
+	t.Run("Panic", func(t *testing.T) {
+		setupTestEnv(t)
+		a, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+			panic("test panic")
+		}, testOptions()...)
+		require.NoError(t, err)
+		require.NoError(t, a.Start(context.Background()))
+		require.ErrorContains(t, a.Wait(), "test panic")
+	})
+	t.Run("Watchdog", func(t *testing.T) {
+		setupTestEnv(t)
+		release := make(chan struct{})
+		t.Cleanup(func() { close(release) })
+		a, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+			<-release // ignoring shutdown
+			return nil
+		}, testOptions(
+			WithShutdownTimeout(time.Millisecond*10),
+			WithWatchdogTimeout(time.Millisecond*10),
+		)...)
+		require.NoError(t, err)
+		require.NoError(t, a.Start(context.Background()))
+		a.Shutdown()
+		require.ErrorIs(t, a.Wait(), ErrWatchdog)
+	})
+	t.Run("NotStarted", func(t *testing.T) {
+		setupTestEnv(t)
+		a, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+			return nil
+		}, testOptions()...)
+		require.NoError(t, err)
+		require.NoError(t, a.Stop(context.Background()))
+		require.Error(t, a.Start(context.Background()))
+	})
+	t.Run("InvalidEnv", func(t *testing.T) {
+		setupTestEnv(t)
+		t.Setenv("SHUTDOWN_TIMEOUT", "forever")
+		_, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+			return nil
+		}, testOptions()...)
+		require.ErrorContains(t, err, "SHUTDOWN_TIMEOUT")
+	})
+}
+
+func TestApp_ConfigFile(t *testing.T) {
+	setupTestEnv(t)
+	name := filepath.Join(t.TempDir(), "otel.yaml")
+	require.NoError(t, os.WriteFile(name, []byte(`file_format: "0.3"
+resource:
+  attributes:
+    - name: service.name
+      value: ${SERVICE:-config}
+propagator:
+  composite: [baggage]
+tracer_provider:
+  processors:
+    - simple:
+        exporter:
+          console: {}
+`), 0o600))
+	t.Setenv("OTEL_CONFIG_FILE", name)
+
+	a, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+		return nil
+	}, testOptions(
+		WithTracerOptions(autotracer.WithWriter(io.Discard)),
+	)...)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, a.Stop(context.Background())) }()
+
+	m := a.Telemetry()
+	v, ok := m.resource.Set().Value(semconv.ServiceNameKey)
+	require.True(t, ok)
+	require.Equal(t, "config", v.AsString())
+	require.Equal(t, []string{"baggage"}, m.TextMapPropagator().Fields())
+	require.IsType(t, &sdktrace.TracerProvider{}, m.providers.tracer)
+
+	t.Run("Invalid", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(name, []byte("file_format: '0.3'\nmeter_provider: []"), 0o600))
+		_, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+			return nil
+		}, testOptions()...)
+		require.ErrorContains(t, err, "meter_provider (line 2:17): expected mapping")
+	})
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.27.0"
+
+	"github.com/go-faster/sdk/cliversion"
+)
+
+// buildInfoResource returns resource with service version and VCS revision.
+//
+// Resource is schemaless to be merged with any other resource.
+func buildInfoResource(info cliversion.Info) *resource.Resource {
+	var attrs []attribute.KeyValue
+	if v := info.Version; v != "" {
+		attrs = append(attrs, semconv.ServiceVersion(v))
+	}
+	if v := info.Commit; v != "" {
+		attrs = append(attrs, semconv.VCSRepositoryRefRevision(v))
+	}
+	return resource.NewSchemaless(attrs...)
+}
+
+// registerBuildInfo registers build_info gauge which is always 1, with
+// build information as attributes.
+func (m *Telemetry) registerBuildInfo(info cliversion.Info) error {
+	attrs := metric.WithAttributeSet(attribute.NewSet(
+		attribute.String("version", info.Version),
+		attribute.String("commit", info.Commit),
+		attribute.Bool("modified", info.Modified),
+		attribute.String("go_version", info.GoVersion),
+	))
+	_, err := m.MeterProvider().Meter(instrumentationName).Int64ObservableGauge("build_info",
+		metric.WithDescription("Build information of application, value is always 1"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			o.Observe(1, attrs)
+			return nil
+		}),
+	)
+	return err
+}
@@ -0,0 +1,75 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.27.0"
+	"go.uber.org/zap"
+
+	"github.com/go-faster/sdk/cliversion"
+)
+
+func TestBuildInfoResource(t *testing.T) {
+	info := cliversion.Info{
+		Version:   "v1.2.3",
+		Commit:    "8b5bf7a",
+		GoVersion: "go1.25.0",
+	}
+	user := resource.NewSchemaless(
+		semconv.ServiceName("app"),
+		semconv.ServiceVersion("v2.0.0"),
+	)
+	res, err := resource.Merge(buildInfoResource(info), user)
+	require.NoError(t, err)
+
+	set := res.Set()
+	version, ok := set.Value(semconv.ServiceVersionKey)
+	require.True(t, ok)
+	require.Equal(t, "v2.0.0", version.AsString(), "explicit attribute should win")
+	commit, ok := set.Value(semconv.VCSRepositoryRefRevisionKey)
+	require.True(t, ok)
+	require.Equal(t, "8b5bf7a", commit.AsString())
+
+	require.Empty(t, buildInfoResource(cliversion.Info{}).Attributes())
+}
+
+func TestTelemetry_registerBuildInfo(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkmetric.NewManualReader()
+	m := &Telemetry{
+		lg:            zap.NewNop(),
+		meterProvider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	}
+	require.NoError(t, m.registerBuildInfo(cliversion.Info{
+		Version:   "v1.2.3",
+		Commit:    "8b5bf7a",
+		Modified:  true,
+		GoVersion: "go1.25.0",
+	}))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+
+	got := rm.ScopeMetrics[0].Metrics[0]
+	require.Equal(t, "build_info", got.Name)
+	gauge, ok := got.Data.(metricdata.Gauge[int64])
+	require.True(t, ok)
+	require.Len(t, gauge.DataPoints, 1)
+
+	dp := gauge.DataPoints[0]
+	require.Equal(t, int64(1), dp.Value)
+	require.Equal(t, attribute.NewSet(
+		attribute.String("version", "v1.2.3"),
+		attribute.String("commit", "8b5bf7a"),
+		attribute.Bool("modified", true),
+		attribute.String("go_version", "go1.25.0"),
+	), dp.Attributes)
+}
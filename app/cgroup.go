@@ -0,0 +1,124 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/go-faster/errors"
+)
+
+// defaultCgroupMount is cgroup v2 mount point if it is not found in mountinfo.
+const defaultCgroupMount = "sys/fs/cgroup"
+
+// cgroupMount returns mount point of cgroup v2 unified hierarchy relative
+// to fsys root.
+func cgroupMount(fsys fs.FS) string {
+	data, err := fs.ReadFile(fsys, "proc/self/mountinfo")
+	if err != nil {
+		return defaultCgroupMount
+	}
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		// 35 24 0:30 / /sys/fs/cgroup rw,nosuid shared:9 - cgroup2 cgroup2 rw
+		fields, fsType, ok := strings.Cut(s.Text(), " - ")
+		if !ok || !strings.HasPrefix(fsType, "cgroup2 ") {
+			continue
+		}
+		if f := strings.Fields(fields); len(f) > 4 {
+			return strings.TrimPrefix(f[4], "/")
+		}
+	}
+	return defaultCgroupMount
+}
+
+// cgroupPath returns cgroup v2 path of current process.
+func cgroupPath(fsys fs.FS) (string, error) {
+	data, err := fs.ReadFile(fsys, "proc/self/cgroup")
+	if err != nil {
+		return "", err
+	}
+	for line := range strings.SplitSeq(string(data), "\n") {
+		// Unified hierarchy has zero ID and empty controllers list.
+		if p, ok := strings.CutPrefix(line, "0::"); ok {
+			return p, nil
+		}
+	}
+	return "", errors.New("cgroup v2 is not used")
+}
+
+// cgroupLimits returns CPU and memory limits of cgroup v2 of current process,
+// which are minimal limits of cgroup and its parents.
+//
+// Zero value means that resource is not limited.
+func cgroupLimits(fsys fs.FS) (cpu float64, mem uint64, _ error) {
+	p, err := cgroupPath(fsys)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "cgroup path")
+	}
+	root := cgroupMount(fsys)
+	dir := path.Join(root, p)
+	for {
+		if v, ok, err := readCPUMax(fsys, path.Join(dir, "cpu.max")); err != nil {
+			return 0, 0, err
+		} else if ok && (cpu == 0 || v < cpu) {
+			cpu = v
+		}
+		if v, ok, err := readMemoryMax(fsys, path.Join(dir, "memory.max")); err != nil {
+			return 0, 0, err
+		} else if ok && (mem == 0 || v < mem) {
+			mem = v
+		}
+		if dir == root || !strings.HasPrefix(dir, root) {
+			return cpu, mem, nil
+		}
+		dir = path.Dir(dir)
+	}
+}
+
+// readCPUMax reads cpu.max file, which is "$MAX $PERIOD" or "max $PERIOD".
+func readCPUMax(fsys fs.FS, name string) (float64, bool, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		// Not limited or controller is not enabled.
+		return 0, false, nil
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 || fields[0] == "max" {
+		return 0, false, nil
+	}
+	quota, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "parse %s", name)
+	}
+	period := 100_000.0
+	if len(fields) > 1 {
+		if period, err = strconv.ParseFloat(fields[1], 64); err != nil {
+			return 0, false, errors.Wrapf(err, "parse %s", name)
+		}
+	}
+	if period <= 0 {
+		return 0, false, nil
+	}
+	return quota / period, true, nil
+}
+
+// readMemoryMax reads memory.max file, which is number of bytes or "max".
+func readMemoryMax(fsys fs.FS, name string) (uint64, bool, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return 0, false, nil
+	}
+	v := strings.TrimSpace(string(data))
+	if v == "max" || v == "" {
+		return 0, false, nil
+	}
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "parse %s", name)
+	}
+	return n, true, nil
+}
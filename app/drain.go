@@ -0,0 +1,79 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// drain triggers graceful shutdown, entering lame-duck mode first if
+// drain delay is set: readiness checks fail, so load balancer stops routing
+// traffic to the application, and [Telemetry.ShutdownContext] is cancelled
+// only after drain delay.
+//
+// Only first call has effect.
+func (a *App) drain() {
+	a.drainOnce.Do(func() {
+		if d := a.opts.drainDelay; d > 0 {
+			go a.lameDuck(d)
+			return
+		}
+		a.shutdownCancel()
+	})
+}
+
+// lameDuck waits for drain delay in lame-duck mode and cancels shutdown context.
+func (a *App) lameDuck(d time.Duration) {
+	var (
+		lg = a.lg
+		m  = a.t
+	)
+	_, span := m.TracerProvider().Tracer(instrumentationName).Start(a.baseCtx, "app.drain",
+		trace.WithAttributes(attribute.String("app.drain.delay", d.String())),
+	)
+	defer span.End()
+
+	m.health.draining.Store(true)
+	m.SetStatus("Draining")
+	span.AddEvent("lame_duck.start")
+	lg.Info("Entering lame-duck mode", zap.Duration("drain_delay", d))
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		span.AddEvent("lame_duck.end")
+		lg.Info("Drain delay elapsed, shutting down")
+	case <-m.shutdownContext.Done():
+		// Application stopped by itself.
+		span.AddEvent("lame_duck.interrupted")
+	case <-a.done:
+		return
+	}
+	a.shutdownCancel()
+}
+
+// registerDrain registers lame-duck mode gauge.
+func (m *Telemetry) registerDrain() error {
+	meter := m.MeterProvider().Meter(instrumentationName)
+	draining, err := meter.Int64ObservableGauge("app.lame_duck",
+		metric.WithDescription("Whether application is in lame-duck mode, draining traffic before shutdown"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "lame duck")
+	}
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		var v int64
+		if m.health.draining.Load() {
+			v = 1
+		}
+		o.ObserveInt64(draining, v)
+		return nil
+	}, draining)
+	return err
+}
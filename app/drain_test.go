@@ -0,0 +1,50 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestApp_Drain(t *testing.T) {
+	setupTestEnv(t)
+	t.Setenv("SHUTDOWN_DRAIN_DELAY", "100ms")
+
+	started := make(chan struct{})
+	a, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}, testOptions()...)
+	require.NoError(t, err)
+	require.Equal(t, 100*time.Millisecond, a.opts.drainDelay)
+
+	m := a.Telemetry()
+	ctx := context.Background()
+	require.NoError(t, a.Start(ctx))
+	<-started
+	require.Equal(t, healthStatusOK, runHealthChecks(ctx, m.health.readinessChecks()).Status)
+
+	start := time.Now()
+	a.Shutdown()
+	require.Eventually(t, func() bool {
+		res := runHealthChecks(ctx, m.health.readinessChecks())
+		return res.Status == healthStatusFail
+	}, time.Second, time.Millisecond*5)
+	require.NoError(t, m.ShutdownContext().Err(), "should be in lame-duck mode")
+
+	require.NoError(t, a.Wait())
+	require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestApp_DrainInvalid(t *testing.T) {
+	setupTestEnv(t)
+	t.Setenv("SHUTDOWN_DRAIN_DELAY", "soon")
+	_, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+		return nil
+	}, testOptions()...)
+	require.Error(t, err)
+}
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// errorLogInterval is interval in which same OpenTelemetry error is logged once.
+const errorLogInterval = time.Minute
+
+// zapErrorHandler is [otel.ErrorHandler] that logs errors with rate limiting.
+//
+// Each distinct error message is logged once per interval, repeated errors
+// are counted and reported as summary at the end of interval, so collector
+// outage does not flood logs.
+type zapErrorHandler struct {
+	lg       *zap.Logger
+	interval time.Duration
+
+	mux        sync.Mutex
+	suppressed map[string]int // error message -> count in current interval
+}
+
+func newZapErrorHandler(lg *zap.Logger, interval time.Duration) *zapErrorHandler {
+	return &zapErrorHandler{
+		lg:         lg,
+		interval:   interval,
+		suppressed: map[string]int{},
+	}
+}
+
+func (z *zapErrorHandler) Handle(err error) {
+	msg := err.Error()
+
+	z.mux.Lock()
+	if _, ok := z.suppressed[msg]; ok {
+		z.suppressed[msg]++
+		z.mux.Unlock()
+		return
+	}
+	z.suppressed[msg] = 0
+	z.mux.Unlock()
+
+	z.lg.Error("Error", zap.Error(err))
+	time.AfterFunc(z.interval, func() { z.flush(msg) })
+}
+
+// flush ends interval of error message, reporting suppressed errors.
+func (z *zapErrorHandler) flush(msg string) {
+	z.mux.Lock()
+	n := z.suppressed[msg]
+	delete(z.suppressed, msg)
+	z.mux.Unlock()
+
+	if n == 0 {
+		return
+	}
+	z.lg.Warn("Similar errors suppressed",
+		zap.String("error", msg),
+		zap.Int("suppressed", n),
+		zap.Duration("interval", z.interval),
+	)
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestZapErrorHandler(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	h := newZapErrorHandler(zap.New(core), 50*time.Millisecond)
+
+	for range 10 {
+		h.Handle(errors.New("connection refused"))
+	}
+	h.Handle(errors.New("deadline exceeded"))
+	require.Equal(t, 2, logs.FilterMessage("Error").Len())
+
+	require.Eventually(t, func() bool {
+		return logs.FilterMessage("Similar errors suppressed").Len() == 1
+	}, time.Second, 10*time.Millisecond)
+	entry := logs.FilterMessage("Similar errors suppressed").All()[0]
+	require.Equal(t, "connection refused", entry.ContextMap()["error"])
+	require.Equal(t, int64(9), entry.ContextMap()["suppressed"])
+
+	// Next interval.
+	require.Eventually(t, func() bool {
+		h.mux.Lock()
+		defer h.mux.Unlock()
+		return len(h.suppressed) == 0
+	}, time.Second, 10*time.Millisecond)
+	h.Handle(errors.New("connection refused"))
+	require.Equal(t, 3, logs.FilterMessage("Error").Len())
+}
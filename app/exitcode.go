@@ -0,0 +1,94 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/go-faster/errors"
+)
+
+const (
+	exitCodeOk             = 0
+	exitCodeApplicationErr = 1
+	exitCodeSignal         = 2
+	exitCodeWatchdog       = 3
+	exitCodePanic          = 4
+)
+
+// exitCodeError is an error with process exit code.
+type exitCodeError struct {
+	err  error
+	code int
+}
+
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
+// ExitCode annotates err with process exit code that is used by [Run]
+// if err is returned by [RunFunc], e.g. to distinguish configuration error
+// from runtime failure.
+//
+// Returns nil if err is nil. Outermost exit code takes precedence.
+func ExitCode(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+	return &exitCodeError{err: err, code: code}
+}
+
+// exitCode returns process exit code for application error.
+func exitCode(err error) int {
+	var e *exitCodeError
+	switch {
+	case err == nil:
+		return exitCodeOk
+	case errors.As(err, &e):
+		return e.code
+	case errors.Is(err, ErrWatchdog):
+		return exitCodeWatchdog
+	case errors.Is(err, ErrForcedShutdown):
+		return exitCodeSignal
+	case errors.Is(err, ErrPanic):
+		return exitCodePanic
+	default:
+		return exitCodeApplicationErr
+	}
+}
+
+// errorChain returns messages of err and errors it wraps, depth-first.
+//
+// Wrappers that do not add to message, like [ExitCode], are skipped.
+func errorChain(err error) []string {
+	var chain []string
+	var walk func(err error)
+	walk = func(err error) {
+		if err == nil {
+			return
+		}
+		if msg := err.Error(); len(chain) == 0 || chain[len(chain)-1] != msg {
+			chain = append(chain, msg)
+		}
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			walk(e.Unwrap())
+		case interface{ Unwrap() []error }:
+			for _, err := range e.Unwrap() {
+				walk(err)
+			}
+		}
+	}
+	walk(err)
+	return chain
+}
+
+// panicError returns error for recovered panic value.
+func panicError(v any) error {
+	if err, ok := v.(error); ok {
+		return fmt.Errorf("%w: %w", ErrPanic, err)
+	}
+	return fmt.Errorf("%w: %v", ErrPanic, v)
+}
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestExitCode(t *testing.T) {
+	testErr := errors.New("test error")
+	require.NoError(t, ExitCode(nil, 10))
+
+	for _, tt := range []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"Ok", nil, exitCodeOk},
+		{"Error", testErr, exitCodeApplicationErr},
+		{"Watchdog", ErrWatchdog, exitCodeWatchdog},
+		{"Signal", ErrForcedShutdown, exitCodeSignal},
+		{"Panic", panicError("boom"), exitCodePanic},
+		{"Explicit", ExitCode(testErr, 78), 78},
+		{"Wrapped", errors.Wrap(ExitCode(testErr, 78), "run"), 78},
+		{"Outermost", ExitCode(errors.Wrap(ExitCode(testErr, 78), "run"), 10), 10},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.code, exitCode(tt.err))
+		})
+	}
+
+	err := errors.Wrap(ExitCode(testErr, 78), "load config")
+	require.ErrorIs(t, err, testErr)
+	require.Equal(t, "load config: test error", err.Error())
+	require.Equal(t, []string{"load config: test error", "test error"}, errorChain(err))
+	require.Equal(t, []string{"a\nb", "a", "b"}, errorChain(errors.Join(errors.New("a"), errors.New("b"))))
+}
+
+func TestApp_ExitCode(t *testing.T) {
+	t.Run("Error", func(t *testing.T) {
+		setupTestEnv(t)
+		a, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+			return ExitCode(errors.New("invalid config"), 78)
+		}, testOptions()...)
+		require.NoError(t, err)
+		require.NoError(t, a.Start(context.Background()))
+		require.Equal(t, 78, exitCode(a.Wait()))
+	})
+	t.Run("Panic", func(t *testing.T) {
+		setupTestEnv(t)
+		a, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+			panic("test panic")
+		}, testOptions()...)
+		require.NoError(t, err)
+		require.NoError(t, a.Start(context.Background()))
+		err = a.Wait()
+		require.ErrorIs(t, err, ErrPanic)
+		require.Equal(t, exitCodePanic, exitCode(err))
+	})
+}
+
+func TestLogExit(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	err := errors.Wrap(ErrForcedShutdown, "wait")
+	require.Equal(t, exitCodeSignal, logExit(zap.New(core), err))
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	require.Equal(t, zapcore.WarnLevel, entries[0].Level)
+	fields := entries[0].ContextMap()
+	require.Equal(t, int64(exitCodeSignal), fields["exit_code"])
+	require.Equal(t, []any{"wait: forced shutdown by signal", "forced shutdown by signal"}, fields["error_chain"])
+}
@@ -0,0 +1,174 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-faster/errors"
+	"go.uber.org/zap"
+)
+
+// healthCheckTimeout limits single health check execution time.
+const healthCheckTimeout = time.Second * 5
+
+// HealthCheck reports health of application component.
+//
+// Returned non-nil error means that component is not healthy.
+type HealthCheck func(ctx context.Context) error
+
+type healthCheck struct {
+	name string
+	fn   HealthCheck
+}
+
+// healthChecks is a registry of health checks.
+type healthChecks struct {
+	mux       sync.Mutex
+	readiness []healthCheck
+	liveness  []healthCheck
+
+	// draining is set in lame-duck mode, failing readiness.
+	draining atomic.Bool
+}
+
+// errDraining is reported by readiness check in lame-duck mode.
+var errDraining = errors.New("shutting down")
+
+// drainCheck returns readiness check that fails in lame-duck mode, if any.
+func (h *healthChecks) drainCheck() []healthCheck {
+	if !h.draining.Load() {
+		return nil
+	}
+	return []healthCheck{{
+		name: "shutdown",
+		fn:   func(context.Context) error { return errDraining },
+	}}
+}
+
+func (h *healthChecks) addReadiness(name string, fn HealthCheck) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	h.readiness = append(h.readiness, healthCheck{name: name, fn: fn})
+}
+
+func (h *healthChecks) addLiveness(name string, fn HealthCheck) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	h.liveness = append(h.liveness, healthCheck{name: name, fn: fn})
+}
+
+func (h *healthChecks) readinessChecks() []healthCheck {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	return include(h.readiness, h.drainCheck()...)
+}
+
+func (h *healthChecks) livenessChecks() []healthCheck {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	return slices.Clone(h.liveness)
+}
+
+func (h *healthChecks) allChecks() []healthCheck {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	return include(include(h.liveness, h.readiness...), h.drainCheck()...)
+}
+
+const (
+	healthStatusOK   = "ok"
+	healthStatusFail = "fail"
+)
+
+type healthCheckResult struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+	Latency string `json:"latency"`
+}
+
+type healthResponse struct {
+	Status string              `json:"status"`
+	Checks []healthCheckResult `json:"checks"`
+}
+
+// runHealthChecks runs all checks in parallel and returns aggregated result.
+func runHealthChecks(ctx context.Context, checks []healthCheck) healthResponse {
+	res := healthResponse{
+		Status: healthStatusOK,
+		Checks: make([]healthCheckResult, len(checks)),
+	}
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Go(func() {
+			ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := c.fn(ctx)
+			r := healthCheckResult{
+				Name:    c.name,
+				Status:  healthStatusOK,
+				Latency: time.Since(start).String(),
+			}
+			if err != nil {
+				r.Status = healthStatusFail
+				r.Error = err.Error()
+			}
+			res.Checks[i] = r
+		})
+	}
+	wg.Wait()
+	for _, r := range res.Checks {
+		if r.Status != healthStatusOK {
+			res.Status = healthStatusFail
+			break
+		}
+	}
+	return res
+}
+
+// healthHandler returns handler that executes checks returned by getChecks.
+//
+// Responds with 200 if all checks passed, otherwise with 503.
+func (m *Telemetry) healthHandler(getChecks func() []healthCheck) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := runHealthChecks(r.Context(), getChecks())
+		code := http.StatusOK
+		if res.Status != healthStatusOK {
+			code = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(code)
+		if err := json.NewEncoder(w).Encode(res); err != nil {
+			m.lg.Debug("Failed to write health response", zap.Error(err))
+		}
+	})
+}
+
+// registerHealth mounts health handlers on endpoint e and admin endpoint.
+func (m *Telemetry) registerHealth(e *httpEndpoint) {
+	m.mount(e, "/healthz", m.healthHandler(m.health.allChecks))
+	m.mount(e, "/readyz", m.healthHandler(m.health.readinessChecks))
+	m.mount(e, "/livez", m.healthHandler(m.health.livenessChecks))
+}
+
+// AddReadinessCheck registers readiness check that is reported on /readyz and /healthz.
+//
+// Failing readiness check means that application should not receive traffic.
+func (m *Telemetry) AddReadinessCheck(name string, fn HealthCheck) {
+	m.health.addReadiness(name, fn)
+}
+
+// AddLivenessCheck registers liveness check that is reported on /livez and /healthz.
+//
+// Failing liveness check means that application should be restarted.
+func (m *Telemetry) AddLivenessCheck(name string, fn HealthCheck) {
+	m.health.addLiveness(name, fn)
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestTelemetry_Health(t *testing.T) {
+	m := &Telemetry{lg: zaptest.NewLogger(t)}
+	e := m.registerEndpoint("localhost:0", "health")
+	m.registerHealth(e)
+
+	ready := errors.New("not ready")
+	m.AddLivenessCheck("alive", func(ctx context.Context) error { return nil })
+	m.AddReadinessCheck("ready", func(ctx context.Context) error { return ready })
+
+	s := httptest.NewServer(e.mux)
+	t.Cleanup(s.Close)
+
+	get := func(t *testing.T, path string) (int, healthResponse) {
+		t.Helper()
+		res, err := s.Client().Get(s.URL + path)
+		require.NoError(t, err)
+		defer func() { _ = res.Body.Close() }()
+		require.Equal(t, "application/json", res.Header.Get("Content-Type"))
+
+		var out healthResponse
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&out))
+		return res.StatusCode, out
+	}
+
+	t.Run("Liveness", func(t *testing.T) {
+		code, res := get(t, "/livez")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, healthStatusOK, res.Status)
+		require.Len(t, res.Checks, 1)
+		require.Equal(t, "alive", res.Checks[0].Name)
+		require.NotEmpty(t, res.Checks[0].Latency)
+	})
+	t.Run("Readiness", func(t *testing.T) {
+		code, res := get(t, "/readyz")
+		require.Equal(t, http.StatusServiceUnavailable, code)
+		require.Equal(t, healthStatusFail, res.Status)
+		require.Len(t, res.Checks, 1)
+		require.Equal(t, healthStatusFail, res.Checks[0].Status)
+		require.Equal(t, "not ready", res.Checks[0].Error)
+	})
+	t.Run("Health", func(t *testing.T) {
+		ready = nil
+		code, res := get(t, "/healthz")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, healthStatusOK, res.Status)
+		require.Len(t, res.Checks, 2)
+	})
+}
+
+func TestTelemetry_registerEndpoint(t *testing.T) {
+	m := &Telemetry{}
+	a := m.registerEndpoint("localhost:8080", "prometheus")
+	b := m.registerEndpoint("localhost:8080", "health")
+	c := m.registerEndpoint("localhost:8081", "pprof")
+	require.Same(t, a, b)
+	require.NotSame(t, a, c)
+	require.Len(t, m.http, 2)
+	require.Equal(t, []string{"prometheus", "health"}, m.http[0].services)
+}
@@ -0,0 +1,179 @@
+package app
+
+import (
+	"bytes"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// leakIgnore is list of creation site prefixes of goroutines that are
+// not reported as leaked: runtime, telemetry SDK and application internals.
+var leakIgnore = []string{
+	"runtime.",
+	"runtime/",
+	"os/signal.",
+	"testing.",
+	"net/http.(*Transport).",
+	"go.opentelemetry.io/",
+	"google.golang.org/grpc",
+	"github.com/grafana/pyroscope-go",
+	"github.com/go-faster/sdk/app.(*App).",
+}
+
+// goroutine is parsed goroutine from stack dump.
+type goroutine struct {
+	id int
+	// createdBy is creation site, function and position, e.g.
+	// "main.run in goroutine 1 at /src/main.go:42".
+	createdBy string
+	// function is creation site function.
+	function string
+	stack    string
+}
+
+// dumpGoroutines returns stacks of all goroutines.
+func dumpGoroutines() []byte {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+}
+
+// parseGoroutines parses output of [runtime.Stack].
+func parseGoroutines(dump []byte) []goroutine {
+	var out []goroutine
+	for block := range bytes.SplitSeq(bytes.TrimSpace(dump), []byte("\n\n")) {
+		stack := string(block)
+		header, _, _ := strings.Cut(stack, "\n")
+		// goroutine 1 [running]:
+		idStr, _, _ := strings.Cut(strings.TrimPrefix(header, "goroutine "), " ")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			continue
+		}
+		g := goroutine{id: id, stack: stack}
+		if _, created, ok := strings.Cut(stack, "\ncreated by "); ok {
+			fn, pos, _ := strings.Cut(created, "\n")
+			pos = strings.TrimSpace(pos)
+			// Dropping PC offset.
+			if i := strings.LastIndex(pos, " +0x"); i > 0 {
+				pos = pos[:i]
+			}
+			g.function, _, _ = strings.Cut(fn, " in goroutine ")
+			g.createdBy = g.function + " at " + pos
+		}
+		out = append(out, g)
+	}
+	return out
+}
+
+func (g goroutine) ignored() bool {
+	if g.function == "" {
+		// Main goroutine.
+		return true
+	}
+	for _, prefix := range leakIgnore {
+		if strings.HasPrefix(g.function, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// leakGroup is group of leaked goroutines with same creation site.
+type leakGroup struct {
+	createdBy string
+	count     int
+	stack     string // of first goroutine
+}
+
+// leakChecker finds goroutines that were started after snapshot and
+// are still running.
+type leakChecker struct {
+	baseline map[int]struct{}
+}
+
+func newLeakChecker() *leakChecker {
+	c := &leakChecker{baseline: map[int]struct{}{}}
+	for _, g := range parseGoroutines(dumpGoroutines()) {
+		c.baseline[g.id] = struct{}{}
+	}
+	return c
+}
+
+// leaks returns leaked goroutines grouped by creation site, most
+// frequent first.
+func (c *leakChecker) leaks() []leakGroup {
+	groups := map[string]*leakGroup{}
+	for _, g := range parseGoroutines(dumpGoroutines()) {
+		if _, ok := c.baseline[g.id]; ok || g.ignored() {
+			continue
+		}
+		lg, ok := groups[g.createdBy]
+		if !ok {
+			lg = &leakGroup{createdBy: g.createdBy, stack: g.stack}
+			groups[g.createdBy] = lg
+		}
+		lg.count++
+	}
+	out := make([]leakGroup, 0, len(groups))
+	for _, g := range groups {
+		out = append(out, *g)
+	}
+	slices.SortFunc(out, func(a, b leakGroup) int {
+		if a.count != b.count {
+			return b.count - a.count
+		}
+		return strings.Compare(a.createdBy, b.createdBy)
+	})
+	return out
+}
+
+// waitLeaks returns leaks that are still present after timeout, giving
+// stopping goroutines time to exit.
+func (c *leakChecker) waitLeaks(timeout time.Duration) []leakGroup {
+	deadline := time.Now().Add(timeout)
+	delay := time.Millisecond
+	for {
+		leaks := c.leaks()
+		if len(leaks) == 0 || time.Now().After(deadline) {
+			return leaks
+		}
+		time.Sleep(delay)
+		delay = min(delay*2, 100*time.Millisecond)
+	}
+}
+
+// reportLeaks logs goroutines that outlived shutdown.
+func (a *App) reportLeaks() {
+	if a.leaks == nil {
+		return
+	}
+	leaks := a.leaks.waitLeaks(time.Second / 2)
+	if len(leaks) == 0 {
+		a.lg.Debug("No goroutine leaks found")
+		return
+	}
+	var total int
+	for _, g := range leaks {
+		total += g.count
+		a.lg.Warn("Goroutine leak",
+			zap.String("created_by", g.createdBy),
+			zap.Int("count", g.count),
+			zap.String("stack", g.stack),
+		)
+	}
+	a.lg.Warn("Goroutines leaked after shutdown",
+		zap.Int("total", total),
+		zap.Int("sites", len(leaks)),
+	)
+}
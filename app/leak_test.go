@@ -0,0 +1,101 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestParseGoroutines(t *testing.T) {
+	const dump = `goroutine 1 [running]:
+main.main()
+	/src/main.go:10 +0x1d
+
+goroutine 7 [chan receive]:
+main.worker(0xc000010000)
+	/src/main.go:20 +0x25
+created by main.main in goroutine 1
+	/src/main.go:12 +0x85
+
+goroutine 8 [select]:
+go.opentelemetry.io/otel/sdk/trace.(*batchSpanProcessor).processQueue(0xc000100000)
+	/mod/batch_span_processor.go:300 +0x1b2
+created by go.opentelemetry.io/otel/sdk/trace.NewBatchSpanProcessor in goroutine 1
+	/mod/batch_span_processor.go:120 +0x3a5
+`
+	goroutines := parseGoroutines([]byte(dump))
+	require.Len(t, goroutines, 3)
+
+	require.Equal(t, 1, goroutines[0].id)
+	require.Empty(t, goroutines[0].createdBy)
+	require.True(t, goroutines[0].ignored())
+
+	require.Equal(t, 7, goroutines[1].id)
+	require.Equal(t, "main.main", goroutines[1].function)
+	require.Equal(t, "main.main at /src/main.go:12", goroutines[1].createdBy)
+	require.Contains(t, goroutines[1].stack, "main.worker")
+	require.False(t, goroutines[1].ignored())
+
+	require.True(t, goroutines[2].ignored())
+}
+
+func leakFor(c *leakChecker, name string) (count int) {
+	for _, g := range c.leaks() {
+		if strings.Contains(g.createdBy, name) {
+			count += g.count
+		}
+	}
+	return count
+}
+
+func TestLeakChecker(t *testing.T) {
+	c := newLeakChecker()
+	require.Zero(t, leakFor(c, "TestLeakChecker"))
+
+	done := make(chan struct{})
+	for range 2 {
+		go func() { <-done }()
+	}
+	require.Equal(t, 2, leakFor(c, "TestLeakChecker"))
+
+	close(done)
+	require.Eventually(t, func() bool {
+		return leakFor(c, "TestLeakChecker") == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestApp_GoroutineLeakCheck(t *testing.T) {
+	setupTestEnv(t)
+	core, logs := observer.New(zapcore.WarnLevel)
+	done := make(chan struct{})
+	defer close(done)
+
+	a, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+		go func() { <-done }()
+		return nil
+	}, testOptions(
+		WithGoroutineLeakCheck(),
+		WithZapOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+			return zapcore.NewTee(c, core)
+		})),
+	)...)
+	require.NoError(t, err)
+	require.NoError(t, a.Start(context.Background()))
+	require.NoError(t, a.Wait())
+
+	var found bool
+	for _, e := range logs.FilterMessage("Goroutine leak").All() {
+		if strings.Contains(e.ContextMap()["created_by"].(string), "TestApp_GoroutineLeakCheck") {
+			found = true
+			require.EqualValues(t, 1, e.ContextMap()["count"])
+		}
+	}
+	require.True(t, found, "leak should be reported")
+	require.Len(t, logs.FilterMessage("Goroutines leaked after shutdown").All(), 1)
+}
@@ -0,0 +1,197 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-faster/errors"
+	"go.uber.org/zap"
+)
+
+// Hook is a lifecycle hook.
+type Hook func(ctx context.Context) error
+
+// Phase of application shutdown.
+//
+// Phases are executed sequentially in order, each with its own timeout
+// within shared shutdown timeout, hooks of the same phase are executed in parallel.
+type Phase int
+
+const (
+	// PhaseServers stops accepting new work, e.g. shuts down HTTP servers.
+	PhaseServers Phase = iota
+	// PhaseApplication drains application work.
+	//
+	// Return of application function is awaited in this phase.
+	PhaseApplication
+	// PhaseTelemetry flushes traces and metrics.
+	PhaseTelemetry
+	// PhaseLogs flushes logs, so logs emitted during previous phases are not lost.
+	PhaseLogs
+
+	phaseCount = iota
+)
+
+// String implements [fmt.Stringer].
+func (p Phase) String() string {
+	switch p {
+	case PhaseServers:
+		return "servers"
+	case PhaseApplication:
+		return "application"
+	case PhaseTelemetry:
+		return "telemetry"
+	case PhaseLogs:
+		return "logs"
+	default:
+		return fmt.Sprintf("Phase(%d)", int(p))
+	}
+}
+
+type hook struct {
+	name string
+	fn   Hook
+}
+
+// lifecycle is a registry of lifecycle hooks.
+type lifecycle struct {
+	mux     sync.Mutex
+	start   []hook
+	stop    [phaseCount][]hook
+	started bool
+}
+
+// OnStart registers hook that is executed on application start before application function.
+//
+// Start hooks are executed sequentially in registration order with [App.Start] context,
+// first failed hook aborts start. Hooks registered after start are not executed.
+func (m *Telemetry) OnStart(name string, fn Hook) {
+	m.hooks.mux.Lock()
+	defer m.hooks.mux.Unlock()
+	if m.hooks.started {
+		m.lg.Warn("Start hook registered after start, ignoring", zap.String("hook", name))
+		return
+	}
+	m.hooks.start = append(m.hooks.start, hook{name: name, fn: fn})
+}
+
+// OnStop registers hook that is executed on application shutdown in given phase.
+func (m *Telemetry) OnStop(phase Phase, name string, fn Hook) {
+	if phase < 0 || phase >= phaseCount {
+		panic(fmt.Sprintf("invalid phase %d", phase))
+	}
+	m.hooks.mux.Lock()
+	defer m.hooks.mux.Unlock()
+	m.hooks.stop[phase] = append(m.hooks.stop[phase], hook{name: name, fn: fn})
+}
+
+// start executes start hooks.
+func (m *Telemetry) start(ctx context.Context) error {
+	m.hooks.mux.Lock()
+	hooks := m.hooks.start
+	m.hooks.started = true
+	m.hooks.mux.Unlock()
+
+	for _, h := range hooks {
+		start := time.Now()
+		if err := h.fn(ctx); err != nil {
+			return errors.Wrapf(err, "start %q", h.name)
+		}
+		m.lg.Debug("Started", zap.String("hook", h.name), zap.Duration("duration", time.Since(start)))
+	}
+	return nil
+}
+
+// shutdownBudget returns total time given to shutdown phases.
+func (m *Telemetry) shutdownBudget() time.Duration {
+	if m.shutdownTimeout != 0 {
+		return m.shutdownTimeout
+	}
+	return defaultShutdownTimeout
+}
+
+// shutdown executes stop hooks phase by phase.
+//
+// Phases share shutdown timeout: phase timeout is capped by time left, so
+// shutdown ends before base context is cancelled and watchdog is started.
+func (m *Telemetry) shutdown(ctx context.Context) {
+	defer m.lg.Debug("Shut down")
+	ctx, cancel := context.WithTimeout(ctx, m.shutdownBudget())
+	defer cancel()
+	for p := range Phase(phaseCount) {
+		m.hooks.mux.Lock()
+		hooks := m.hooks.stop[p]
+		m.hooks.mux.Unlock()
+		if len(hooks) == 0 {
+			continue
+		}
+		m.shutdownPhase(ctx, p, hooks)
+	}
+}
+
+// shutdownPhase launches hooks in parallel and waits for them or phase timeout.
+//
+// Hooks that overran phase timeout are reported and abandoned.
+func (m *Telemetry) shutdownPhase(ctx context.Context, p Phase, hooks []hook) {
+	// Deadline is always set by shutdown.
+	deadline, _ := ctx.Deadline()
+	timeout := time.Until(deadline)
+	if d, ok := m.phaseTimeouts[p]; ok && d < timeout {
+		timeout = d
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var (
+		mux      sync.Mutex
+		finished = make([]bool, len(hooks))
+		names    = make([]string, 0, len(hooks))
+		wg       sync.WaitGroup
+	)
+	for _, h := range hooks {
+		names = append(names, h.name)
+	}
+	lg := m.lg.With(zap.Stringer("phase", p))
+	lg.Info("Waiting for shutdowns", zap.Strings("shutdowns", names))
+	for i, h := range hooks {
+		wg.Go(func() {
+			start := time.Now()
+			if err := h.fn(ctx); err != nil {
+				lg.Error("Failed to shutdown", zap.Error(err), zap.String("name", h.name))
+			}
+			lg.Debug("Stopped", zap.String("name", h.name), zap.Duration("duration", time.Since(start)))
+
+			mux.Lock()
+			finished[i] = true
+			mux.Unlock()
+		})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		var overran []string
+		mux.Lock()
+		for i, ok := range finished {
+			if !ok {
+				overran = append(overran, names[i])
+			}
+		}
+		mux.Unlock()
+		if len(overran) == 0 {
+			// Finished right at the deadline.
+			return
+		}
+		lg.Warn("Shutdown phase timed out",
+			zap.Duration("timeout", timeout),
+			zap.Strings("overran", overran),
+		)
+	}
+}
@@ -0,0 +1,160 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestTelemetry_shutdown(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	m := &Telemetry{
+		lg: zap.New(core),
+		phaseTimeouts: map[Phase]time.Duration{
+			PhaseApplication: time.Millisecond * 10,
+		},
+	}
+
+	var (
+		mux   sync.Mutex
+		order []string
+	)
+	record := func(name string) Hook {
+		return func(ctx context.Context) error {
+			mux.Lock()
+			defer mux.Unlock()
+			order = append(order, name)
+			return nil
+		}
+	}
+	// Registering in reverse order.
+	m.OnStop(PhaseLogs, "logger", record("logger"))
+	m.OnStop(PhaseTelemetry, "tracer", record("tracer"))
+	m.OnStop(PhaseApplication, "stuck", func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(time.Millisecond * 50) // overrun
+		return ctx.Err()
+	})
+	m.OnStop(PhaseServers, "http", record("http"))
+
+	m.shutdown(context.Background())
+	require.Equal(t, []string{"http", "tracer", "logger"}, order)
+
+	timedOut := logs.FilterMessage("Shutdown phase timed out").All()
+	require.Len(t, timedOut, 1)
+	fields := timedOut[0].ContextMap()
+	require.Equal(t, "application", fields["phase"])
+	require.Equal(t, []any{"stuck"}, fields["overran"])
+}
+
+func TestTelemetry_shutdownBudget(t *testing.T) {
+	const budget = time.Millisecond * 100
+	m := &Telemetry{
+		lg:              zap.NewNop(),
+		shutdownTimeout: budget,
+		phaseTimeouts: map[Phase]time.Duration{
+			PhaseLogs: time.Hour,
+		},
+	}
+
+	var deadlines []time.Time
+	record := func(ctx context.Context) error {
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok)
+		deadlines = append(deadlines, deadline)
+		return nil
+	}
+	m.OnStop(PhaseServers, "stuck", func(ctx context.Context) error {
+		time.Sleep(budget / 2)
+		return nil
+	})
+	m.OnStop(PhaseTelemetry, "tracer", record)
+	m.OnStop(PhaseLogs, "logger", record)
+
+	start := time.Now()
+	m.shutdown(context.Background())
+	require.Less(t, time.Since(start), budget)
+
+	// Later phases get time left, even if own timeout is longer.
+	require.Len(t, deadlines, 2)
+	for _, deadline := range deadlines {
+		require.WithinDuration(t, start.Add(budget), deadline, budget/4)
+	}
+}
+
+func TestTelemetry_start(t *testing.T) {
+	m := &Telemetry{lg: zap.NewNop()}
+
+	var called []string
+	m.OnStart("first", func(ctx context.Context) error {
+		called = append(called, "first")
+		return nil
+	})
+	m.OnStart("second", func(ctx context.Context) error {
+		return errors.New("failed")
+	})
+	m.OnStart("third", func(ctx context.Context) error {
+		called = append(called, "third")
+		return nil
+	})
+
+	require.ErrorContains(t, m.start(context.Background()), `start "second": failed`)
+	require.Equal(t, []string{"first"}, called)
+
+	// Ignored after start.
+	m.OnStart("late", func(ctx context.Context) error {
+		t.Fatal("should not be called")
+		return nil
+	})
+	require.Len(t, m.hooks.start, 3)
+}
+
+func TestApp_hooks(t *testing.T) {
+	setupTestEnv(t)
+
+	var (
+		mux   sync.Mutex
+		order []string
+	)
+	record := func(name string) {
+		mux.Lock()
+		defer mux.Unlock()
+		order = append(order, name)
+	}
+	running := make(chan struct{})
+	a, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+		record("run")
+		close(running)
+		<-ctx.Done()
+		time.Sleep(time.Millisecond * 10) // draining
+		record("drained")
+		return ctx.Err()
+	}, testOptions()...)
+	require.NoError(t, err)
+
+	m := a.Telemetry()
+	m.OnStart("start", func(ctx context.Context) error {
+		record("start")
+		return nil
+	})
+	m.OnStop(PhaseTelemetry, "flush", func(ctx context.Context) error {
+		record("flush")
+		return nil
+	})
+	m.OnStop(PhaseServers, "server", func(ctx context.Context) error {
+		record("server")
+		return nil
+	})
+
+	require.NoError(t, a.Start(context.Background()))
+	<-running
+	require.NoError(t, a.Stop(context.Background()))
+	require.Equal(t, []string{"start", "run", "server", "drained", "flush"}, order)
+}
@@ -0,0 +1,219 @@
+package app
+
+import (
+	"context"
+	"io/fs"
+	"math"
+	"math/bits"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/KimMachineGun/automemlimit/memlimit"
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/automaxprocs/maxprocs"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMemLimitRatio = 0.9
+	defaultLimitsRefresh = 30 * time.Second
+)
+
+// memLimitProvider returns memory limit provider by name.
+//
+// Fixed provider uses limit from AUTOMEMLIMIT_FIXED environment variable.
+func memLimitProvider(name string) (memlimit.Provider, error) {
+	switch name {
+	case "cgroup":
+		return memlimit.FromCgroup, nil
+	case "system":
+		return memlimit.FromSystem, nil
+	case "fixed":
+		v := os.Getenv("AUTOMEMLIMIT_FIXED")
+		if v == "" {
+			return nil, errors.New("AUTOMEMLIMIT_FIXED is required by fixed provider")
+		}
+		limit, err := parseBytes(v)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse AUTOMEMLIMIT_FIXED")
+		}
+		return memlimit.Limit(limit), nil
+	default:
+		return nil, errors.Errorf("unknown provider %q", name)
+	}
+}
+
+// parseBytes parses size in GOMEMLIMIT format, e.g. "512MiB" or "1073741824".
+func parseBytes(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	shift := 0
+	for i, suffix := range []string{"KiB", "MiB", "GiB", "TiB"} {
+		if v, ok := strings.CutSuffix(s, suffix); ok {
+			s, shift = v, 10*(i+1)
+			break
+		}
+	}
+	s = strings.TrimSuffix(s, "B")
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if bits.LeadingZeros64(n) < shift {
+		return 0, errors.Errorf("%q overflows", s)
+	}
+	return n << shift, nil
+}
+
+// runtimeLimits sets GOMAXPROCS and GOMEMLIMIT from container limits.
+type runtimeLimits struct {
+	lg   *zap.Logger
+	fsys fs.FS
+
+	maxProcs bool
+	minProcs int
+	// memLimit provides GOMEMLIMIT, nil if disabled.
+	memLimit memlimit.Provider
+
+	// Container limits, zero if not limited.
+	cpu atomic.Uint64 // float64 bits
+	mem atomic.Uint64
+}
+
+func newRuntimeLimits(lg *zap.Logger, opts options) *runtimeLimits {
+	r := &runtimeLimits{
+		lg:       lg,
+		fsys:     os.DirFS("/"),
+		maxProcs: opts.maxProcs,
+		minProcs: opts.minProcs,
+	}
+	switch {
+	case !opts.memLimit:
+		lg.Debug("Memory limit tuning is disabled")
+	case os.Getenv("GOMEMLIMIT") != "":
+		lg.Debug("GOMEMLIMIT is set, skipping memory limit tuning")
+	default:
+		r.memLimit = memlimit.ApplyRatio(opts.memLimitProvider, opts.memLimitRatio)
+	}
+	return r
+}
+
+// apply reads container limits and updates GOMAXPROCS and GOMEMLIMIT
+// if they are changed.
+func (r *runtimeLimits) apply(initial bool) {
+	// Errors are expected to repeat on refresh.
+	logErr := r.lg.Debug
+	if initial {
+		logErr = r.lg.Warn
+	}
+	if cpu, mem, err := cgroupLimits(r.fsys); err != nil {
+		r.lg.Debug("Failed to read cgroup limits", zap.Error(err))
+	} else {
+		r.cpu.Store(math.Float64bits(cpu))
+		r.mem.Store(mem)
+	}
+	if r.maxProcs {
+		// Logging only changes.
+		prev := runtime.GOMAXPROCS(0)
+		if _, err := maxprocs.Set(maxprocs.Min(r.minProcs)); err != nil {
+			logErr("Failed to set GOMAXPROCS", zap.Error(err))
+		} else if n := runtime.GOMAXPROCS(0); n != prev {
+			r.lg.Info("GOMAXPROCS updated", zap.Int("gomaxprocs", n), zap.Int("previous", prev))
+		}
+	}
+	if r.memLimit != nil {
+		limit, err := r.memLimit()
+		if errors.Is(err, memlimit.ErrNoLimit) {
+			limit, err = math.MaxInt64, nil
+		}
+		if err != nil {
+			logErr("Failed to get memory limit", zap.Error(err))
+		} else if prev := debug.SetMemoryLimit(-1); int64(limit) != prev {
+			debug.SetMemoryLimit(int64(limit))
+			r.lg.Info("GOMEMLIMIT updated", zap.Uint64("gomemlimit", limit), zap.Int64("previous", prev))
+		}
+	}
+	if initial {
+		r.lg.Info("Runtime limits",
+			zap.Int("gomaxprocs", runtime.GOMAXPROCS(0)),
+			zap.Int64("gomemlimit", debug.SetMemoryLimit(-1)),
+			zap.Float64("container.cpu.limit", math.Float64frombits(r.cpu.Load())),
+			zap.Uint64("container.memory.limit", r.mem.Load()),
+		)
+	}
+}
+
+// register registers gauges of effective and container limits.
+func (r *runtimeLimits) register(mp metric.MeterProvider) error {
+	meter := mp.Meter(instrumentationName)
+	procs, err := meter.Int64ObservableGauge("app.runtime.gomaxprocs",
+		metric.WithDescription("Effective GOMAXPROCS"),
+		metric.WithUnit("{thread}"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "gomaxprocs")
+	}
+	memLimit, err := meter.Int64ObservableGauge("app.runtime.gomemlimit",
+		metric.WithDescription("Effective GOMEMLIMIT, not reported if not limited"),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "gomemlimit")
+	}
+	cpu, err := meter.Float64ObservableGauge("app.container.cpu.limit",
+		metric.WithDescription("CPU limit of container cgroup, not reported if not limited"),
+		metric.WithUnit("{cpu}"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "container cpu limit")
+	}
+	mem, err := meter.Int64ObservableGauge("app.container.memory.limit",
+		metric.WithDescription("Memory limit of container cgroup, not reported if not limited"),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "container memory limit")
+	}
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(procs, int64(runtime.GOMAXPROCS(0)))
+		if v := debug.SetMemoryLimit(-1); v != math.MaxInt64 {
+			o.ObserveInt64(memLimit, v)
+		}
+		if v := math.Float64frombits(r.cpu.Load()); v > 0 {
+			o.ObserveFloat64(cpu, v)
+		}
+		if v := r.mem.Load(); v > 0 {
+			o.ObserveInt64(mem, int64(min(v, math.MaxInt64)))
+		}
+		return nil
+	}, procs, memLimit, cpu, mem)
+	return err
+}
+
+// refreshLimits periodically re-applies runtime limits, e.g. after in-place
+// resize of pod.
+func (a *App) refreshLimits() {
+	d := a.opts.limitsRefresh
+	if a.limits == nil || d <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(d)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				a.limits.apply(false)
+			case <-a.t.shutdownContext.Done():
+				return
+			case <-a.done:
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,138 @@
+package app
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCgroupLimits(t *testing.T) {
+	t.Run("Nested", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"proc/self/cgroup": {Data: []byte("0::/kubepods/pod1/app\n")},
+			"proc/self/mountinfo": {Data: []byte(
+				"24 1 0:22 / /sys rw - sysfs sysfs rw\n" +
+					"35 24 0:30 / /sys/fs/cgroup rw,nosuid shared:9 - cgroup2 cgroup2 rw\n",
+			)},
+			"sys/fs/cgroup/kubepods/cpu.max":             {Data: []byte("400000 100000\n")},
+			"sys/fs/cgroup/kubepods/memory.max":          {Data: []byte("max\n")},
+			"sys/fs/cgroup/kubepods/pod1/cpu.max":        {Data: []byte("max 100000\n")},
+			"sys/fs/cgroup/kubepods/pod1/memory.max":     {Data: []byte("1073741824\n")},
+			"sys/fs/cgroup/kubepods/pod1/app/cpu.max":    {Data: []byte("150000 100000\n")},
+			"sys/fs/cgroup/kubepods/pod1/app/memory.max": {Data: []byte("2147483648\n")},
+		}
+		cpu, mem, err := cgroupLimits(fsys)
+		require.NoError(t, err)
+		require.Equal(t, 1.5, cpu)
+		require.Equal(t, uint64(1<<30), mem)
+	})
+	t.Run("Unlimited", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"proc/self/cgroup":           {Data: []byte("0::/\n")},
+			"sys/fs/cgroup/cgroup.procs": {Data: []byte("1\n")},
+		}
+		cpu, mem, err := cgroupLimits(fsys)
+		require.NoError(t, err)
+		require.Zero(t, cpu)
+		require.Zero(t, mem)
+	})
+	t.Run("Hybrid", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"proc/self/cgroup": {Data: []byte("4:memory:/app\n0::/app\n")},
+			"proc/self/mountinfo": {Data: []byte(
+				"40 30 0:35 / /sys/fs/cgroup/unified rw - cgroup2 cgroup2 rw\n",
+			)},
+			"sys/fs/cgroup/unified/app/memory.max": {Data: []byte("1048576")},
+		}
+		cpu, mem, err := cgroupLimits(fsys)
+		require.NoError(t, err)
+		require.Zero(t, cpu)
+		require.Equal(t, uint64(1<<20), mem)
+	})
+	t.Run("V1", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"proc/self/cgroup": {Data: []byte("4:memory:/app\n")},
+		}
+		_, _, err := cgroupLimits(fsys)
+		require.Error(t, err)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"proc/self/cgroup":      {Data: []byte("0::/\n")},
+			"sys/fs/cgroup/cpu.max": {Data: []byte("many 100000")},
+		}
+		_, _, err := cgroupLimits(fsys)
+		require.Error(t, err)
+	})
+}
+
+func TestParseBytes(t *testing.T) {
+	for _, tt := range []struct {
+		input  string
+		output uint64
+	}{
+		{"1024", 1024},
+		{"1024B", 1024},
+		{"512KiB", 512 << 10},
+		{"256MiB", 256 << 20},
+		{" 4GiB ", 4 << 30},
+		{"1TiB", 1 << 40},
+	} {
+		t.Run(tt.input, func(t *testing.T) {
+			v, err := parseBytes(tt.input)
+			require.NoError(t, err)
+			require.Equal(t, tt.output, v)
+		})
+	}
+	for _, input := range []string{"", "1GB", "-1", "many", "99999999999999TiB"} {
+		_, err := parseBytes(input)
+		require.Error(t, err, input)
+	}
+}
+
+func TestMemoryLimitOptions(t *testing.T) {
+	t.Run("Env", func(t *testing.T) {
+		t.Setenv("AUTOMEMLIMIT", "0.5")
+		t.Setenv("AUTOMEMLIMIT_PROVIDER", "fixed")
+		t.Setenv("AUTOMEMLIMIT_FIXED", "1GiB")
+		t.Setenv("LIMITS_REFRESH_INTERVAL", "1m")
+		opts, err := buildOptions(nil)
+		require.NoError(t, err)
+		require.True(t, opts.memLimit)
+		require.Equal(t, 0.5, opts.memLimitRatio)
+		require.Equal(t, time.Minute, opts.limitsRefresh)
+
+		limit, err := opts.memLimitProvider()
+		require.NoError(t, err)
+		require.Equal(t, uint64(1<<30), limit)
+	})
+	t.Run("Off", func(t *testing.T) {
+		t.Setenv("AUTOMEMLIMIT", "off")
+		opts, err := buildOptions(nil)
+		require.NoError(t, err)
+		require.False(t, opts.memLimit)
+	})
+	t.Run("Option", func(t *testing.T) {
+		t.Setenv("AUTOMEMLIMIT", "0.5")
+		opts, err := buildOptions([]Option{WithMemoryLimitRatio(0.8), WithoutMemoryLimit()})
+		require.NoError(t, err)
+		require.Equal(t, 0.8, opts.memLimitRatio)
+		require.False(t, opts.memLimit)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		for env, value := range map[string]string{
+			"AUTOMEMLIMIT":            "half",
+			"AUTOMEMLIMIT_PROVIDER":   "fixed",
+			"LIMITS_REFRESH_INTERVAL": "often",
+		} {
+			t.Run(env, func(t *testing.T) {
+				t.Setenv(env, value)
+				t.Setenv("AUTOMEMLIMIT_FIXED", "")
+				_, err := buildOptions(nil)
+				require.ErrorContains(t, err, env)
+			})
+		}
+	})
+}
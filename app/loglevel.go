@@ -0,0 +1,150 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-faster/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// logLevelHandler implements runtime log level control.
+//
+// GET returns current level, PUT sets new level with optional TTL, after
+// which level is reverted to previous one.
+//
+//	curl -X PUT 'localhost:9010/debug/loglevel?level=debug&ttl=5m'
+//	curl -X PUT localhost:9010/debug/loglevel -d '{"level":"debug","ttl":"5m"}'
+type logLevelHandler struct {
+	lg    *zap.Logger
+	level zap.AtomicLevel
+
+	mux      sync.Mutex
+	revert   *time.Timer
+	revertTo zapcore.Level
+	revertAt time.Time
+}
+
+func newLogLevelHandler(lg *zap.Logger, level zap.AtomicLevel) *logLevelHandler {
+	return &logLevelHandler{
+		lg:    lg,
+		level: level,
+	}
+}
+
+type logLevelPayload struct {
+	Level    string     `json:"level"`
+	TTL      string     `json:"ttl,omitempty"`
+	RevertTo string     `json:"revert_to,omitempty"`
+	RevertAt *time.Time `json:"revert_at,omitempty"`
+}
+
+type logLevelError struct {
+	Error string `json:"error"`
+}
+
+func (h *logLevelHandler) state() logLevelPayload {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	p := logLevelPayload{
+		Level: h.level.Level().String(),
+	}
+	if h.revert != nil {
+		at := h.revertAt
+		p.RevertTo = h.revertTo.String()
+		p.RevertAt = &at
+	}
+	return p
+}
+
+// set sets level and schedules revert if ttl is positive.
+//
+// Pending revert is cancelled, but original level is kept as revert target.
+func (h *logLevelHandler) set(level zapcore.Level, ttl time.Duration) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	revertTo := h.level.Level()
+	if h.revert != nil {
+		h.revert.Stop()
+		revertTo = h.revertTo
+		h.revert = nil
+	}
+	h.level.SetLevel(level)
+	h.lg.Info("Log level changed",
+		zap.Stringer("level", level),
+		zap.Duration("ttl", ttl),
+	)
+	if ttl <= 0 {
+		return
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(ttl, func() {
+		h.mux.Lock()
+		defer h.mux.Unlock()
+		if h.revert != timer {
+			// Cancelled or replaced.
+			return
+		}
+		h.revert = nil
+		h.level.SetLevel(revertTo)
+		h.lg.Info("Log level reverted", zap.Stringer("level", revertTo))
+	})
+	h.revert = timer
+	h.revertTo = revertTo
+	h.revertAt = time.Now().Add(ttl)
+}
+
+func (h *logLevelHandler) parse(r *http.Request) (zapcore.Level, time.Duration, error) {
+	var p logLevelPayload
+	if err := r.ParseForm(); err != nil {
+		return 0, 0, errors.Wrap(err, "parse form")
+	}
+	if r.Form.Has("level") {
+		p.Level = r.Form.Get("level")
+		p.TTL = r.Form.Get("ttl")
+	} else if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		return 0, 0, errors.Wrap(err, "decode")
+	}
+
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(p.Level)); err != nil {
+		return 0, 0, errors.Wrap(err, "level")
+	}
+	var ttl time.Duration
+	if p.TTL != "" {
+		d, err := time.ParseDuration(p.TTL)
+		if err != nil {
+			return 0, 0, errors.Wrap(err, "ttl")
+		}
+		ttl = d
+	}
+	return level, ttl, nil
+}
+
+func (h *logLevelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		level, ttl, err := h.parse(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = enc.Encode(logLevelError{Error: err.Error()})
+			return
+		}
+		h.set(level, ttl)
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = enc.Encode(logLevelError{Error: "only GET and PUT are supported"})
+		return
+	}
+	_ = enc.Encode(h.state())
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestLogLevelHandler(t *testing.T) {
+	level := zap.NewAtomicLevelAt(zap.InfoLevel)
+	h := newLogLevelHandler(zaptest.NewLogger(t), level)
+	s := httptest.NewServer(h)
+	t.Cleanup(s.Close)
+
+	do := func(t *testing.T, method, query, body string) (int, logLevelPayload) {
+		t.Helper()
+		req, err := http.NewRequest(method, s.URL+"/debug/loglevel"+query, strings.NewReader(body))
+		require.NoError(t, err)
+		res, err := s.Client().Do(req)
+		require.NoError(t, err)
+		defer func() { _ = res.Body.Close() }()
+
+		var out logLevelPayload
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&out))
+		return res.StatusCode, out
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		code, p := do(t, http.MethodGet, "", "")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "info", p.Level)
+		require.Nil(t, p.RevertAt)
+	})
+	t.Run("PutJSON", func(t *testing.T) {
+		code, p := do(t, http.MethodPut, "", `{"level":"warn"}`)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "warn", p.Level)
+		require.Equal(t, zap.WarnLevel, level.Level())
+	})
+	t.Run("PutQuery", func(t *testing.T) {
+		code, p := do(t, http.MethodPut, "?level=error", "")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "error", p.Level)
+		require.Equal(t, zap.ErrorLevel, level.Level())
+	})
+	t.Run("BadRequest", func(t *testing.T) {
+		code, _ := do(t, http.MethodPut, "?level=verbose", "")
+		require.Equal(t, http.StatusBadRequest, code)
+		require.Equal(t, zap.ErrorLevel, level.Level())
+	})
+	t.Run("TTL", func(t *testing.T) {
+		level.SetLevel(zap.InfoLevel)
+		code, p := do(t, http.MethodPut, "?level=debug&ttl=1h", "")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "debug", p.Level)
+		require.Equal(t, "info", p.RevertTo)
+		require.NotNil(t, p.RevertAt)
+
+		// Replacing pending revert keeps original level as target.
+		_, p = do(t, http.MethodPut, "", `{"level":"warn","ttl":"10ms"}`)
+		require.Equal(t, "info", p.RevertTo)
+		require.Eventually(t, func() bool {
+			return level.Level() == zap.InfoLevel
+		}, time.Second, time.Millisecond*5)
+
+		_, p = do(t, http.MethodGet, "", "")
+		require.Equal(t, "info", p.Level)
+		require.Nil(t, p.RevertAt)
+	})
+}
@@ -2,7 +2,10 @@ package app
 
 import (
 	"context"
+	"os"
+	"time"
 
+	"github.com/KimMachineGun/automemlimit/memlimit"
 	"go.opentelemetry.io/otel/sdk/resource"
 	semconv "go.opentelemetry.io/otel/semconv/v1.27.0"
 	"go.uber.org/zap"
@@ -10,6 +13,7 @@ import (
 	"github.com/go-faster/sdk/autologs"
 	"github.com/go-faster/sdk/autometer"
 	"github.com/go-faster/sdk/autotracer"
+	"github.com/go-faster/sdk/otelconfig"
 )
 
 type options struct {
@@ -25,19 +29,33 @@ type options struct {
 	resourceOptions []resource.Option
 	resourceFn      func(ctx context.Context) (*resource.Resource, error)
 	modulePath      string
+
+	signals         []os.Signal
+	reloadSignals   []os.Signal
+	shutdownTimeout time.Duration
+	drainDelay      time.Duration
+	watchdogTimeout time.Duration
+	phaseTimeouts   map[Phase]time.Duration
+
+	globalState bool
+	leakCheck   bool
+
+	maxProcs         bool
+	minProcs         int
+	memLimit         bool
+	memLimitRatio    float64
+	memLimitProvider memlimit.Provider
+	limitsRefresh    time.Duration
+
+	config *otelconfig.Config
 }
 
 func (o *options) modifyZapConfig(cb func(*zap.Config)) {
 	cb(&o.zapConfig)
 }
 
-func (o *options) buildLogger() *zap.Logger {
-	lg, err := o.zapConfig.Build(o.zapOptions...)
-	if err != nil {
-		panic("failed to build zap logger: " + err.Error())
-	}
-
-	return lg
+func (o *options) buildLogger() (*zap.Logger, error) {
+	return o.zapConfig.Build(o.zapOptions...)
 }
 
 type optionFunc func(*options)
@@ -145,7 +163,9 @@ func WithResource(fn func(ctx context.Context) (*resource.Resource, error)) Opti
 }
 
 // WithModulePath sets the module path used to look up build version information
-// (e.g. "github.com/go-faster/sdk"). If set, version info is logged on startup.
+// (e.g. "github.com/go-faster/sdk"). If set, version info is logged on startup,
+// exposed as build_info metric and added to resource as service.version and
+// vcs.repository.ref.revision attributes, unless they are set explicitly.
 //
 // See [cliversion.GetInfo].
 func WithModulePath(modulePath string) Option {
@@ -153,3 +173,145 @@ func WithModulePath(modulePath string) Option {
 		o.modulePath = modulePath
 	})
 }
+
+// WithShutdownSignals sets signals that trigger graceful shutdown.
+//
+// Defaults to SIGINT and SIGTERM, can be set by SHUTDOWN_SIGNALS environment variable,
+// e.g. "SIGINT,SIGTERM,SIGHUP". No signals are handled if called without arguments.
+func WithShutdownSignals(signals ...os.Signal) Option {
+	return optionFunc(func(o *options) {
+		o.signals = signals
+	})
+}
+
+// WithReloadSignals sets signals that trigger telemetry reload, see [Telemetry.Reload].
+//
+// Defaults to SIGHUP, can be set by RELOAD_SIGNALS environment variable.
+// Signals that trigger graceful shutdown are ignored.
+// No signals are handled if called without arguments.
+func WithReloadSignals(signals ...os.Signal) Option {
+	return optionFunc(func(o *options) {
+		o.reloadSignals = signals
+	})
+}
+
+// WithShutdownTimeout sets time given to application for graceful shutdown
+// before base context is cancelled.
+//
+// Defaults to 5s, can be set by SHUTDOWN_TIMEOUT environment variable.
+func WithShutdownTimeout(d time.Duration) Option {
+	return optionFunc(func(o *options) {
+		o.shutdownTimeout = d
+	})
+}
+
+// WithShutdownDrainDelay sets lame-duck mode duration: on shutdown signal
+// readiness checks start to fail, so load balancer stops routing traffic to
+// application, and [Telemetry.ShutdownContext] is cancelled only after d.
+// Shutdown timeout is counted after drain delay.
+//
+// Disabled by default, can be set by SHUTDOWN_DRAIN_DELAY environment variable.
+func WithShutdownDrainDelay(d time.Duration) Option {
+	return optionFunc(func(o *options) {
+		o.drainDelay = d
+	})
+}
+
+// WithShutdownPhaseTimeout sets timeout of shutdown phase.
+//
+// Phases share shutdown timeout, see [WithShutdownTimeout], which wins over
+// phase timeout: by default phase gets time left by previous phases.
+func WithShutdownPhaseTimeout(phase Phase, d time.Duration) Option {
+	return optionFunc(func(o *options) {
+		if o.phaseTimeouts == nil {
+			o.phaseTimeouts = make(map[Phase]time.Duration)
+		}
+		o.phaseTimeouts[phase] = d
+	})
+}
+
+// WithWatchdogTimeout sets time given to application to stop after base context
+// cancellation before it is forcefully terminated.
+//
+// Defaults to 10s, can be set by WATCHDOG_TIMEOUT environment variable.
+func WithWatchdogTimeout(d time.Duration) Option {
+	return optionFunc(func(o *options) {
+		o.watchdogTimeout = d
+	})
+}
+
+// WithGoroutineLeakCheck enables goroutine leak check: goroutines that
+// were started after [App.Start] and are still running after shutdown are
+// logged, grouped by creation site.
+//
+// Runtime, OpenTelemetry SDK and application internal goroutines are ignored.
+// Can be enabled by GOROUTINE_LEAK_CHECK environment variable.
+func WithGoroutineLeakCheck() Option {
+	return optionFunc(func(o *options) {
+		o.leakCheck = true
+	})
+}
+
+// WithMemoryLimitRatio sets ratio of memory limit that is set as GOMEMLIMIT,
+// leaving headroom for memory that Go runtime is not aware of.
+//
+// Defaults to 0.9, can be set by AUTOMEMLIMIT environment variable.
+func WithMemoryLimitRatio(ratio float64) Option {
+	return optionFunc(func(o *options) {
+		o.memLimitRatio = ratio
+	})
+}
+
+// WithMemoryLimitProvider sets provider of memory limit that is used to set
+// GOMEMLIMIT, e.g. [memlimit.FromSystem] or [memlimit.Limit].
+//
+// Defaults to [memlimit.FromCgroup], can be set by AUTOMEMLIMIT_PROVIDER
+// environment variable.
+func WithMemoryLimitProvider(p memlimit.Provider) Option {
+	return optionFunc(func(o *options) {
+		o.memLimitProvider = p
+	})
+}
+
+// WithoutMemoryLimit disables setting GOMEMLIMIT from memory limit.
+//
+// Same as AUTOMEMLIMIT=off environment variable.
+func WithoutMemoryLimit() Option {
+	return optionFunc(func(o *options) {
+		o.memLimit = false
+	})
+}
+
+// WithLimitsRefreshInterval sets interval of re-applying GOMAXPROCS and
+// GOMEMLIMIT from container limits, so in-place resize of pod is picked up.
+// Zero disables refresh.
+//
+// Defaults to 30s, can be set by LIMITS_REFRESH_INTERVAL environment variable.
+func WithLimitsRefreshInterval(d time.Duration) Option {
+	return optionFunc(func(o *options) {
+		o.limitsRefresh = d
+	})
+}
+
+// WithoutGlobalState disables modification of process-wide state:
+// global OpenTelemetry providers, propagator, logger and error handler,
+// GOMAXPROCS and GOMEMLIMIT.
+//
+// Useful for tests or embedding [App] into another process.
+// Use [Telemetry] methods to access providers.
+func WithoutGlobalState() Option {
+	return optionFunc(func(o *options) {
+		o.globalState = false
+	})
+}
+
+// WithConfig sets OpenTelemetry declarative configuration that is used instead of
+// environment variables to set up resource, propagator, tracer, meter and logger providers.
+//
+// By default, configuration is loaded from file set by OTEL_CONFIG_FILE environment
+// variable, if any. See [otelconfig.Load].
+func WithConfig(c *otelconfig.Config) Option {
+	return optionFunc(func(o *options) {
+		o.config = c
+	})
+}
@@ -10,13 +10,14 @@ import (
 	"github.com/go-faster/sdk/profiler"
 )
 
-func (m *Telemetry) registerProfiler(mux *http.ServeMux) {
+// newProfiler returns pprof handler or nil if disabled by PPROF_ROUTES.
+func (m *Telemetry) newProfiler() http.Handler {
 	var routes []string
 	if v := os.Getenv("PPROF_ROUTES"); v != "" {
 		routes = strings.Split(v, ",")
 	}
 	if len(routes) == 1 && routes[0] == "none" {
-		return
+		return nil
 	}
 	opt := profiler.Options{
 		Routes: routes,
@@ -24,5 +25,5 @@ func (m *Telemetry) registerProfiler(mux *http.ServeMux) {
 			m.lg.Warn("Unknown pprof route", zap.String("route", route))
 		},
 	}
-	mux.Handle("/debug/pprof/", profiler.New(opt))
+	return profiler.New(opt)
 }
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"net"
+	"net/http"
+	"os"
+
+	promClient "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+func prometheusAddr() string {
+	host := "localhost"
+	port := "9464"
+	if v := os.Getenv("OTEL_EXPORTER_PROMETHEUS_HOST"); v != "" {
+		host = v
+	}
+	if v := os.Getenv("OTEL_EXPORTER_PROMETHEUS_PORT"); v != "" {
+		port = v
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// newPrometheusHandler returns handler for prometheus registry.
+//
+// OpenMetrics format is negotiated by Accept header, which is required
+// to expose exemplars. Responses are compressed if client supports it.
+func newPrometheusHandler(lg *zap.Logger, reg *promClient.Registry) http.Handler {
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{
+		ErrorLog:          zap.NewStdLog(lg.Named("prometheus")),
+		EnableOpenMetrics: true,
+	})
+}
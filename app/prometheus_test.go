@@ -0,0 +1,80 @@
+package app
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	promClient "github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+
+	"github.com/go-faster/sdk/autometer"
+)
+
+func TestPrometheusExemplars(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("OTEL_METRICS_EXPORTER", "prometheus")
+
+	var reg *promClient.Registry
+	provider, stop, err := autometer.NewMeterProvider(ctx,
+		autometer.WithOnPrometheusRegistry(func(r *promClient.Registry) {
+			reg = r
+		}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = stop(ctx) })
+	require.NotNil(t, reg)
+
+	hist, err := provider.Meter("test").Float64Histogram("request.duration",
+		metric.WithUnit("s"),
+	)
+	require.NoError(t, err)
+
+	tracerProvider := sdktrace.NewTracerProvider()
+	spanCtx, span := tracerProvider.Tracer("test").Start(ctx, "request")
+	hist.Record(spanCtx, 0.42)
+	span.End()
+	traceID := span.SpanContext().TraceID().String()
+
+	s := httptest.NewServer(newPrometheusHandler(zap.NewNop(), reg))
+	t.Cleanup(s.Close)
+
+	scrape := func(t *testing.T, accept string) (*http.Response, string) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, s.URL, http.NoBody)
+		require.NoError(t, err)
+		req.Header.Set("Accept", accept)
+		req.Header.Set("Accept-Encoding", "gzip")
+		// Disabling transparent decompression to check encoding.
+		res, err := (&http.Client{Transport: &http.Transport{DisableCompression: true}}).Do(req)
+		require.NoError(t, err)
+		defer func() { _ = res.Body.Close() }()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, "gzip", res.Header.Get("Content-Encoding"))
+
+		r, err := gzip.NewReader(res.Body)
+		require.NoError(t, err)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		return res, string(data)
+	}
+
+	t.Run("OpenMetrics", func(t *testing.T) {
+		res, body := scrape(t, "application/openmetrics-text;version=1.0.0")
+		require.Contains(t, res.Header.Get("Content-Type"), "application/openmetrics-text")
+		require.Contains(t, body, `request_duration_seconds_bucket`)
+		require.Contains(t, body, `trace_id="`+traceID+`"`)
+	})
+	t.Run("Text", func(t *testing.T) {
+		res, body := scrape(t, "text/plain")
+		require.Contains(t, res.Header.Get("Content-Type"), "text/plain")
+		require.Contains(t, body, `request_duration_seconds_bucket`)
+		require.NotContains(t, body, `trace_id=`)
+	})
+}
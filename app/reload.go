@@ -0,0 +1,295 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"sync/atomic"
+
+	"github.com/go-faster/errors"
+	promClient "github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/go-faster/sdk/autologs"
+	"github.com/go-faster/sdk/autometer"
+	"github.com/go-faster/sdk/autotracer"
+)
+
+// providers are telemetry providers that are re-created on reload.
+type providers struct {
+	tracer     trace.TracerProvider
+	tracerStop Hook
+	meter      metric.MeterProvider
+	meterStop  Hook
+	logger     log.LoggerProvider
+	loggerStop Hook
+
+	// prom is prometheus registry of meter provider, if any.
+	prom *promClient.Registry
+}
+
+// newProviders creates tracer, meter and logger providers from options.
+func (m *Telemetry) newProviders(ctx context.Context, res *resource.Resource, opts options) (p providers, rerr error) {
+	defer func() {
+		if rerr != nil {
+			_ = p.shutdown(ctx)
+		}
+	}()
+	if c := opts.config; c != nil {
+		opts.loggerOptions = include(opts.loggerOptions, autologs.WithConfig(c))
+		opts.tracerOptions = include(opts.tracerOptions, autotracer.WithConfig(c))
+		opts.meterOptions = include(opts.meterOptions, autometer.WithConfig(c))
+	}
+	if m.tracez != nil {
+		opts.tracerOptions = include(opts.tracerOptions, autotracer.WithSpanProcessor(m.tracez))
+	}
+	if s := m.stats; s != nil {
+		opts.loggerOptions = include(opts.loggerOptions, autologs.WithStats(s))
+		opts.tracerOptions = include(opts.tracerOptions, autotracer.WithStats(s))
+		opts.meterOptions = include(opts.meterOptions, autometer.WithStats(s))
+	}
+	{
+		done := m.startup.phase("logger_provider")
+		provider, stop, err := autologs.NewLoggerProvider(ctx,
+			include(opts.loggerOptions,
+				autologs.WithResource(res),
+				autologs.WithLevel(opts.zapConfig.Level),
+			)...,
+		)
+		done(err)
+		if err != nil {
+			return p, errors.Wrap(err, "logger provider")
+		}
+		p.logger, p.loggerStop = provider, Hook(stop)
+	}
+	{
+		done := m.startup.phase("tracer_provider")
+		provider, stop, err := autotracer.NewTracerProvider(ctx,
+			include(opts.tracerOptions,
+				autotracer.WithResource(res),
+			)...,
+		)
+		done(err)
+		if err != nil {
+			return p, errors.Wrap(err, "tracer provider")
+		}
+		p.tracer, p.tracerStop = provider, Hook(stop)
+	}
+	{
+		done := m.startup.phase("meter_provider")
+		provider, stop, err := autometer.NewMeterProvider(ctx,
+			include(opts.meterOptions,
+				autometer.WithResource(res),
+				autometer.WithOnPrometheusRegistry(func(reg *promClient.Registry) {
+					p.prom = reg
+				}),
+			)...,
+		)
+		done(err)
+		if err != nil {
+			return p, errors.Wrap(err, "meter provider")
+		}
+		p.meter, p.meterStop = provider, Hook(stop)
+	}
+	return p, nil
+}
+
+// shutdown flushes and shuts down providers.
+func (p providers) shutdown(ctx context.Context) error {
+	var errs []error
+	for _, s := range []struct {
+		name string
+		stop Hook
+	}{
+		{"tracer", p.tracerStop},
+		{"meter", p.meterStop},
+		{"logger", p.loggerStop},
+	} {
+		if s.stop == nil {
+			continue
+		}
+		if err := s.stop(ctx); err != nil {
+			errs = append(errs, errors.Wrap(err, s.name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// stopHook returns hook that shuts down current provider selected by f.
+func (m *Telemetry) stopHook(f func(p providers) Hook) Hook {
+	return func(ctx context.Context) error {
+		m.reloadMux.Lock()
+		defer m.reloadMux.Unlock()
+		return f(m.providers)(ctx)
+	}
+}
+
+// swapHandler is [http.Handler] that can be replaced at runtime.
+//
+// Responds with 404 if handler is not set.
+type swapHandler struct {
+	h atomic.Pointer[http.Handler]
+}
+
+func newSwapHandler(h http.Handler) *swapHandler {
+	s := new(swapHandler)
+	s.set(h)
+	return s
+}
+
+func (s *swapHandler) set(h http.Handler) {
+	if h == nil {
+		s.h.Store(nil)
+		return
+	}
+	s.h.Store(&h)
+}
+
+func (s *swapHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h := s.h.Load(); h != nil {
+		(*h).ServeHTTP(w, r)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// Reload re-reads environment variables and configuration file, replacing
+// tracer, meter and logger providers.
+//
+// Tracers, meters, instruments and loggers obtained from [Telemetry] providers
+// keep working and use new providers. Previous providers are shut down,
+// flushing pending telemetry. If new providers can't be created, current ones are kept.
+//
+// Log level, resource, exporters, sampler and pprof routes are reloaded.
+// Listen addresses, propagator and shutdown settings require restart.
+//
+// Reload is triggered by SIGHUP, see [WithReloadSignals], and by POST request to
+// /debug/reload on admin server.
+func (m *Telemetry) Reload(ctx context.Context) error {
+	if m.reload == nil {
+		return errors.New("reload is not supported")
+	}
+	m.reloadMux.Lock()
+	defer m.reloadMux.Unlock()
+	if m.shutdownContext.Err() != nil {
+		return errors.New("shutting down")
+	}
+
+	// Providers outlive reload request.
+	createCtx := context.WithoutCancel(ctx)
+	opts, res, err := m.reload(createCtx)
+	if err != nil {
+		return err
+	}
+	// Keeping runtime log level control.
+	level := opts.zapConfig.Level.Level()
+	opts.zapConfig.Level = m.level
+	next, err := m.newProviders(createCtx, res, opts)
+	if err != nil {
+		return err
+	}
+	prev := m.providers
+	m.providers = next
+	m.resource = res
+	m.tracerDelegate.Set(next.tracer)
+	m.loggerDelegate.Set(next.logger)
+	if _, err := m.meterDelegate.Set(next.meter); err != nil {
+		m.lg.Warn("Failed to re-create some instruments", zap.Error(err))
+	}
+	m.level.SetLevel(level)
+
+	m.prom = next.prom
+	switch {
+	case m.promHandler != nil && next.prom != nil:
+		m.promHandler.set(newPrometheusHandler(m.lg, next.prom))
+	case m.promHandler != nil:
+		m.promHandler.set(nil)
+	case next.prom != nil:
+		m.lg.Warn("Prometheus exporter is enabled by reload, restart is required to serve metrics")
+	}
+	if m.pprofHandler != nil {
+		m.pprofHandler.set(m.newProfiler())
+	}
+	m.lg.Info("Telemetry reloaded",
+		zap.Stringer("otel.resource", res),
+		zap.Stringer("level", m.level.Level()),
+	)
+
+	if err := prev.shutdown(ctx); err != nil {
+		return errors.Wrap(err, "shutdown previous providers")
+	}
+	return nil
+}
+
+type reloadResponse struct {
+	Status string `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// reloadHandler triggers [Telemetry.Reload] on POST request.
+//
+//	curl -X POST localhost:9010/debug/reload
+func (m *Telemetry) reloadHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			_ = enc.Encode(reloadResponse{Error: "only POST is supported"})
+			return
+		}
+		if err := m.Reload(r.Context()); err != nil {
+			m.lg.Error("Failed to reload telemetry", zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = enc.Encode(reloadResponse{Error: err.Error()})
+			return
+		}
+		_ = enc.Encode(reloadResponse{Status: "reloaded"})
+	})
+}
+
+// handleReload triggers telemetry reload on reload signals.
+//
+// Signals that trigger shutdown are ignored.
+func (a *App) handleReload() {
+	var signals []os.Signal
+	for _, s := range a.opts.reloadSignals {
+		if slices.Contains(a.opts.signals, s) {
+			continue
+		}
+		signals = append(signals, s)
+	}
+	if len(signals) == 0 {
+		return
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+
+	lg := a.lg
+	go func() {
+		defer signal.Stop(ch)
+		for {
+			select {
+			case s := <-ch:
+				lg.Info("Got signal, reloading telemetry", zap.Stringer("signal", s))
+				ctx, cancel := context.WithTimeout(a.baseCtx, a.opts.shutdownTimeout)
+				if err := a.t.Reload(ctx); err != nil {
+					lg.Error("Failed to reload telemetry", zap.Error(err))
+				}
+				cancel()
+			case <-a.t.shutdownContext.Done():
+				return
+			case <-a.done:
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,136 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	promClient "github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	semconv "go.opentelemetry.io/otel/semconv/v1.27.0"
+	"go.uber.org/zap"
+
+	"github.com/go-faster/sdk/autometer"
+	"github.com/go-faster/sdk/autotracer"
+)
+
+func TestTelemetry_Reload(t *testing.T) {
+	setupTestEnv(t)
+	name := filepath.Join(t.TempDir(), "otel.yaml")
+	writeConfig := func(service string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(name, []byte(`file_format: "0.3"
+resource:
+  attributes:
+    - name: service.name
+      value: `+service+`
+tracer_provider:
+  processors:
+    - simple:
+        exporter:
+          console: {}
+`), 0o600))
+	}
+	writeConfig("first")
+	t.Setenv("OTEL_CONFIG_FILE", name)
+	t.Setenv("PPROF_ROUTES", "none")
+	t.Setenv("ADMIN_ADDR", "127.0.0.1:0")
+
+	var out bytes.Buffer
+	a, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+		return nil
+	}, testOptions(
+		WithTracerOptions(autotracer.WithWriter(&out)),
+	)...)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, a.Stop(context.Background())) }()
+
+	ctx := context.Background()
+	m := a.Telemetry()
+	tracer := m.TracerProvider().Tracer("test")
+	_, span := tracer.Start(ctx, "before")
+	span.End()
+	require.Contains(t, out.String(), `"Value":"first"`)
+	out.Reset()
+
+	// Serving pprof after reload.
+	require.Equal(t, http.StatusNotFound, serve(m.pprofHandler, http.MethodGet, "/debug/pprof/").Code)
+	t.Setenv("PPROF_ROUTES", "")
+
+	writeConfig("second")
+	rec := serve(m.reloadHandler(), http.MethodPost, "/debug/reload")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	_, span = tracer.Start(ctx, "after")
+	span.End()
+	require.Contains(t, out.String(), `"Value":"second"`)
+	v, ok := m.resource.Set().Value(semconv.ServiceNameKey)
+	require.True(t, ok)
+	require.Equal(t, "second", v.AsString())
+	require.Equal(t, http.StatusOK, serve(m.pprofHandler, http.MethodGet, "/debug/pprof/").Code)
+
+	t.Run("Invalid", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(name, []byte("file_format: '0.3'\nmeter_provider: []"), 0o600))
+		require.ErrorContains(t, m.Reload(ctx), "meter_provider (line 2:17): expected mapping")
+
+		// Current providers are kept.
+		out.Reset()
+		_, span = tracer.Start(ctx, "kept")
+		span.End()
+		require.Contains(t, out.String(), `"Value":"second"`)
+
+		rec := serve(m.reloadHandler(), http.MethodPost, "/debug/reload")
+		require.Equal(t, http.StatusInternalServerError, rec.Code)
+		require.Contains(t, rec.Body.String(), "expected mapping")
+	})
+	t.Run("Method", func(t *testing.T) {
+		rec := serve(m.reloadHandler(), http.MethodGet, "/debug/reload")
+		require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
+
+func TestTelemetry_ReloadPrometheus(t *testing.T) {
+	setupTestEnv(t)
+	t.Setenv("OTEL_METRICS_EXPORTER", "prometheus")
+	t.Setenv("METRICS_ADDR", "127.0.0.1:0")
+
+	reg := promClient.NewRegistry()
+	a, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+		return nil
+	}, testOptions(
+		WithMeterOptions(autometer.WithPrometheusRegisterer(reg)),
+	)...)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, a.Stop(context.Background())) }()
+
+	ctx := context.Background()
+	m := a.Telemetry()
+	counter, err := m.MeterProvider().Meter("test").Int64Counter("requests")
+	require.NoError(t, err)
+
+	// Registry is shared by re-created meter providers.
+	for range 2 {
+		require.NoError(t, m.Reload(ctx))
+	}
+	counter.Add(ctx, 1)
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	names := map[string]int{}
+	for _, f := range families {
+		names[f.GetName()]++
+	}
+	require.Equal(t, 1, names["requests_total"])
+	require.Equal(t, 1, names["go_goroutines"])
+	require.Equal(t, 1, names["target_info"])
+}
+
+func serve(h http.Handler, method, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	return rec
+}
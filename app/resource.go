@@ -5,6 +5,8 @@ import (
 
 	"github.com/go-faster/errors"
 	"go.opentelemetry.io/otel/sdk/resource"
+
+	"github.com/go-faster/sdk/autoresource"
 )
 
 func defaultResourceOptions() []resource.Option {
@@ -19,6 +21,8 @@ func defaultResourceOptions() []resource.Option {
 		resource.WithProcessExecutableName(),
 		resource.WithProcessExecutablePath(),
 		resource.WithProcessCommandArgs(),
+		// Container, Kubernetes and OTEL_RESOURCE_ATTRIBUTES_FILE, overridden by environment.
+		resource.WithDetectors(autoresource.Detectors(autoresource.Root())...),
 		resource.WithFromEnv(),
 	}
 }
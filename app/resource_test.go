@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.27.0"
 )
 
 func TestDefaultResourceOptionsWithoutUser(t *testing.T) {
@@ -28,3 +29,13 @@ func TestDefaultResourceOptionsWithoutUser(t *testing.T) {
 		require.NotEqual(t, "process.owner", string(attr.Key))
 	}
 }
+
+func TestDefaultResourceOptionsKubernetes(t *testing.T) {
+	t.Setenv("K8S_POD_NAME", "api-0")
+	t.Setenv("K8S_NAMESPACE_NAME", "prod")
+
+	res, err := resource.New(context.Background(), defaultResourceOptions()...)
+	require.NoError(t, err)
+	require.Contains(t, res.Attributes(), semconv.K8SPodName("api-0"))
+	require.Contains(t, res.Attributes(), semconv.K8SNamespaceName("prod"))
+}
@@ -0,0 +1,157 @@
+package app
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-faster/errors"
+	"go.uber.org/zap"
+)
+
+// sdNotifier sends service state notifications to systemd, see sd_notify(3).
+//
+// Nil *sdNotifier is valid and does nothing.
+type sdNotifier struct {
+	mux  sync.Mutex
+	conn *net.UnixConn
+}
+
+// newSDNotifier connects to systemd notification socket, returning nil
+// if socket is not set.
+func newSDNotifier(socket string) (*sdNotifier, error) {
+	if socket == "" {
+		return nil, nil
+	}
+	if name, ok := strings.CutPrefix(socket, "@"); ok {
+		// Abstract namespace socket.
+		socket = "\x00" + name
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return nil, errors.Wrap(err, "dial")
+	}
+	return &sdNotifier{conn: conn}, nil
+}
+
+// notify sends state variables, e.g. "READY=1".
+func (n *sdNotifier) notify(state ...string) error {
+	if n == nil {
+		return nil
+	}
+	n.mux.Lock()
+	defer n.mux.Unlock()
+	if n.conn == nil {
+		// Application is stopped.
+		return nil
+	}
+	_, err := n.conn.Write([]byte(strings.Join(state, "\n")))
+	return err
+}
+
+func (n *sdNotifier) close() error {
+	if n == nil {
+		return nil
+	}
+	n.mux.Lock()
+	defer n.mux.Unlock()
+	if n.conn == nil {
+		return nil
+	}
+	err := n.conn.Close()
+	n.conn = nil
+	return err
+}
+
+// sdWatchdogInterval returns interval of watchdog keepalives from
+// WATCHDOG_USEC, or zero if watchdog is not enabled for this process.
+func sdWatchdogInterval() (time.Duration, error) {
+	v := os.Getenv("WATCHDOG_USEC")
+	if v == "" {
+		return 0, nil
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		// Watchdog is for another process.
+		return 0, nil
+	}
+	usec, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || usec <= 0 {
+		return 0, errors.Errorf("invalid WATCHDOG_USEC %q", v)
+	}
+	// Sending keepalives twice per timeout, as recommended by sd_watchdog_enabled(3).
+	return time.Duration(usec) * time.Microsecond / 2, nil
+}
+
+// Ready reports that application is started and ready to serve.
+//
+// If application is started by systemd with Type=notify, READY=1 is sent
+// to NOTIFY_SOCKET. Should be called by [RunFunc] once initialized.
+//
+// Time from application start until Ready call is recorded as
+// app.startup.init span and included to app.startup.duration metric.
+func (m *Telemetry) Ready() {
+	m.startup.markReady()
+	if err := m.sd.notify("READY=1", "STATUS=Running"); err != nil {
+		m.lg.Warn("Failed to notify systemd", zap.Error(err))
+	}
+}
+
+// SetStatus sets free-form status of application that is shown by
+// systemctl status, if NOTIFY_SOCKET is set.
+func (m *Telemetry) SetStatus(status string) {
+	if err := m.sd.notify("STATUS=" + status); err != nil {
+		m.lg.Warn("Failed to notify systemd", zap.Error(err))
+	}
+}
+
+// handleSDNotify reports shutdown to systemd and sends watchdog keepalives.
+//
+// Keepalives are sent only while liveness checks pass, so systemd restarts
+// wedged application.
+func (a *App) handleSDNotify() {
+	sd := a.t.sd
+	if sd == nil {
+		return
+	}
+	lg := a.lg
+	interval, err := sdWatchdogInterval()
+	if err != nil {
+		lg.Warn("Systemd watchdog is disabled", zap.Error(err))
+	}
+	go func() {
+		var tick <-chan time.Time
+		if interval > 0 {
+			lg.Debug("Sending systemd watchdog keepalives", zap.Duration("interval", interval))
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+		stopping := a.t.shutdownContext.Done()
+		for {
+			select {
+			case <-tick:
+				ctx, cancel := context.WithTimeout(a.baseCtx, interval)
+				res := runHealthChecks(ctx, a.t.health.livenessChecks())
+				cancel()
+				if res.Status != healthStatusOK {
+					lg.Warn("Liveness checks failed, skipping systemd watchdog keepalive")
+					continue
+				}
+				if err := sd.notify("WATCHDOG=1"); err != nil {
+					lg.Warn("Failed to send systemd watchdog keepalive", zap.Error(err))
+				}
+			case <-stopping:
+				stopping = nil
+				if err := sd.notify("STOPPING=1", "STATUS=Shutting down"); err != nil {
+					lg.Warn("Failed to notify systemd", zap.Error(err))
+				}
+			case <-a.done:
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,120 @@
+package app
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// listenNotify listens systemd notification socket and sets NOTIFY_SOCKET.
+func listenNotify(t *testing.T) <-chan string {
+	t.Helper()
+
+	name := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: name, Net: "unixgram"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	t.Setenv("NOTIFY_SOCKET", name)
+
+	messages := make(chan string, 100)
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				return
+			}
+			messages <- string(buf[:n])
+		}
+	}()
+	return messages
+}
+
+// waitNotify waits for message that contains state.
+func waitNotify(t *testing.T, messages <-chan string, state string) string {
+	t.Helper()
+
+	timeout := time.After(time.Second * 5)
+	for {
+		select {
+		case msg := <-messages:
+			for s := range strings.SplitSeq(msg, "\n") {
+				if s == state {
+					return msg
+				}
+			}
+		case <-timeout:
+			t.Fatalf("no %q notification", state)
+		}
+	}
+}
+
+func TestApp_SDNotify(t *testing.T) {
+	setupTestEnv(t)
+	messages := listenNotify(t)
+	t.Setenv("WATCHDOG_USEC", "20000")
+
+	var alive atomic.Bool
+	alive.Store(true)
+	a, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+		m.AddLivenessCheck("test", func(ctx context.Context) error {
+			if !alive.Load() {
+				return errors.New("wedged")
+			}
+			return nil
+		})
+		m.SetStatus("Initializing")
+		m.Ready()
+		<-ctx.Done()
+		return ctx.Err()
+	}, testOptions()...)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, a.Start(ctx))
+	require.Equal(t, "STATUS=Initializing", waitNotify(t, messages, "STATUS=Initializing"))
+	require.Equal(t, "READY=1\nSTATUS=Running", waitNotify(t, messages, "READY=1"))
+	waitNotify(t, messages, "WATCHDOG=1")
+
+	// Keepalives are stopped if liveness checks fail.
+	alive.Store(false)
+	time.Sleep(50 * time.Millisecond)
+	for len(messages) > 0 {
+		<-messages
+	}
+	time.Sleep(50 * time.Millisecond)
+	require.Empty(t, messages)
+
+	require.NoError(t, a.Stop(ctx))
+	require.Equal(t, "STOPPING=1\nSTATUS=Shutting down", waitNotify(t, messages, "STOPPING=1"))
+}
+
+func TestSDWatchdogInterval(t *testing.T) {
+	t.Setenv("WATCHDOG_USEC", "")
+	d, err := sdWatchdogInterval()
+	require.NoError(t, err)
+	require.Zero(t, d)
+
+	t.Setenv("WATCHDOG_USEC", "30000000")
+	d, err = sdWatchdogInterval()
+	require.NoError(t, err)
+	require.Equal(t, 15*time.Second, d)
+
+	t.Setenv("WATCHDOG_PID", "1")
+	d, err = sdWatchdogInterval()
+	require.NoError(t, err)
+	require.Zero(t, d)
+
+	t.Setenv("WATCHDOG_PID", "")
+	t.Setenv("WATCHDOG_USEC", "soon")
+	_, err = sdWatchdogInterval()
+	require.Error(t, err)
+}
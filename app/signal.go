@@ -0,0 +1,45 @@
+package app
+
+import (
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/go-faster/errors"
+)
+
+// defaultSignals returns signals that trigger graceful shutdown by default.
+func defaultSignals() []os.Signal {
+	return []os.Signal{os.Interrupt, syscall.SIGTERM}
+}
+
+// defaultReloadSignals returns signals that trigger telemetry reload by default.
+func defaultReloadSignals() []os.Signal {
+	return []os.Signal{syscall.SIGHUP}
+}
+
+// parseSignals parses comma-separated list of signal names, like "SIGINT,SIGTERM".
+//
+// The "SIG" prefix is optional and names are case-insensitive.
+func parseSignals(s string) ([]os.Signal, error) {
+	var out []os.Signal
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		switch strings.TrimPrefix(name, "SIG") {
+		case "INT":
+			out = append(out, os.Interrupt)
+		case "TERM":
+			out = append(out, syscall.SIGTERM)
+		case "HUP":
+			out = append(out, syscall.SIGHUP)
+		case "QUIT":
+			out = append(out, syscall.SIGQUIT)
+		default:
+			return nil, errors.Errorf("unknown signal %q", name)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,32 @@
+package app
+
+import (
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSignals(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  []os.Signal
+		err   bool
+	}{
+		{input: "SIGINT,SIGTERM", want: []os.Signal{os.Interrupt, syscall.SIGTERM}},
+		{input: " term , hup ,", want: []os.Signal{syscall.SIGTERM, syscall.SIGHUP}},
+		{input: "SIGQUIT", want: []os.Signal{syscall.SIGQUIT}},
+		{input: "SIGKILL", err: true},
+	} {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseSignals(tt.input)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startupPhase is a recorded startup phase.
+type startupPhase struct {
+	name       string
+	parent     int // index of parent phase, -1 for root
+	start, end time.Time
+	err        error
+}
+
+// startup records startup phases.
+//
+// Tracer provider is not available during most of startup, so phases are
+// buffered and emitted as children of app.startup span once application
+// is started.
+type startup struct {
+	mux    sync.Mutex
+	start  time.Time
+	end    time.Time // zero until emitted
+	ready  time.Time // zero until Telemetry.Ready is called
+	phases []startupPhase
+	stack  []int // indexes of running phases
+	root   trace.SpanContext
+	tracer trace.Tracer
+}
+
+func newStartup(start time.Time) *startup {
+	return &startup{start: start}
+}
+
+// phase records startup phase until returned function is called.
+//
+// Phases started before previous phase is done are recorded as its
+// children. Phases are not recorded after startup is finished or if s is nil.
+func (s *startup) phase(name string) func(err error) {
+	if s == nil {
+		return func(error) {}
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if !s.end.IsZero() {
+		return func(error) {}
+	}
+	parent := -1
+	if n := len(s.stack); n > 0 {
+		parent = s.stack[n-1]
+	}
+	idx := len(s.phases)
+	s.phases = append(s.phases, startupPhase{
+		name:   "app.startup." + name,
+		parent: parent,
+		start:  time.Now(),
+	})
+	s.stack = append(s.stack, idx)
+	return func(err error) {
+		s.mux.Lock()
+		defer s.mux.Unlock()
+		p := &s.phases[idx]
+		p.end = time.Now()
+		p.err = err
+		if n := len(s.stack); n > 0 && s.stack[n-1] == idx {
+			s.stack = s.stack[:n-1]
+		}
+	}
+}
+
+// finish ends startup and emits app.startup span with recorded phases.
+func (s *startup) finish(ctx context.Context, tp trace.TracerProvider) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if !s.end.IsZero() {
+		return
+	}
+	s.end = time.Now()
+	s.tracer = tp.Tracer(instrumentationName)
+
+	ctx, root := s.tracer.Start(ctx, "app.startup",
+		trace.WithTimestamp(s.start),
+		trace.WithNewRoot(),
+	)
+	s.root = root.SpanContext()
+	contexts := make([]context.Context, len(s.phases))
+	for i, p := range s.phases {
+		parent := ctx
+		if p.parent >= 0 {
+			parent = contexts[p.parent]
+		}
+		end := p.end
+		if end.IsZero() {
+			// Not finished phase, e.g. failed.
+			end = s.end
+		}
+		var span trace.Span
+		contexts[i], span = s.tracer.Start(parent, p.name, trace.WithTimestamp(p.start))
+		if p.err != nil {
+			span.RecordError(p.err)
+			span.SetStatus(codes.Error, p.err.Error())
+		}
+		span.End(trace.WithTimestamp(end))
+	}
+	root.End(trace.WithTimestamp(s.end))
+	s.phases = nil
+}
+
+// markReady records application initialization after start as
+// app.startup.init span, if startup is finished.
+//
+// Only first call has effect.
+func (s *startup) markReady() {
+	if s == nil {
+		return
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.end.IsZero() || !s.ready.IsZero() {
+		return
+	}
+	s.ready = time.Now()
+	ctx := trace.ContextWithSpanContext(context.Background(), s.root)
+	_, span := s.tracer.Start(ctx, "app.startup.init", trace.WithTimestamp(s.end))
+	span.End(trace.WithTimestamp(s.ready))
+}
+
+// duration returns startup duration until Telemetry.Ready call or
+// application start.
+func (s *startup) duration() (time.Duration, bool) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	switch {
+	case !s.ready.IsZero():
+		return s.ready.Sub(s.start), true
+	case !s.end.IsZero():
+		return s.end.Sub(s.start), true
+	default:
+		return 0, false
+	}
+}
+
+// registerStartup registers startup duration gauge.
+func (m *Telemetry) registerStartup() error {
+	meter := m.MeterProvider().Meter(instrumentationName)
+	g, err := meter.Float64ObservableGauge("app.startup.duration",
+		metric.WithDescription("Time from application creation until it is ready or started"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "startup duration")
+	}
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		if d, ok := m.startup.duration(); ok {
+			o.ObserveFloat64(g, d.Seconds())
+		}
+		return nil
+	}, g)
+	return err
+}
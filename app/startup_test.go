@@ -0,0 +1,118 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/go-faster/sdk/autometer"
+	"github.com/go-faster/sdk/autotracer"
+)
+
+func TestApp_Startup(t *testing.T) {
+	setupTestEnv(t)
+	t.Setenv("OTEL_METRICS_EXPORTER", "manual")
+	reader := sdkmetric.NewManualReader()
+	recorder := tracetest.NewSpanRecorder()
+
+	ready := make(chan struct{})
+	a, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+		m.Ready()
+		close(ready)
+		<-ctx.Done()
+		return ctx.Err()
+	}, testOptions(
+		WithTracerOptions(autotracer.WithSpanProcessor(recorder)),
+		WithMeterOptions(autometer.WithLookupExporter(func(ctx context.Context, name string) (sdkmetric.Reader, bool, error) {
+			return reader, true, nil
+		})),
+	)...)
+	require.NoError(t, err)
+	require.NoError(t, a.Start(context.Background()))
+	<-ready
+
+	spans := map[string]trace.SpanContext{}
+	parents := map[string]trace.SpanContext{}
+	for _, s := range recorder.Ended() {
+		spans[s.Name()] = s.SpanContext()
+		parents[s.Name()] = s.Parent()
+		require.False(t, s.EndTime().Before(s.StartTime()), s.Name())
+	}
+	root, ok := spans["app.startup"]
+	require.True(t, ok)
+	for _, name := range []string{
+		"app.startup.resource",
+		"app.startup.telemetry",
+		"app.startup.start",
+		"app.startup.init",
+	} {
+		require.Equal(t, root.SpanID(), parents[name].SpanID(), name)
+	}
+	for _, name := range []string{
+		"app.startup.logger_provider",
+		"app.startup.tracer_provider",
+		"app.startup.meter_provider",
+		"app.startup.runtime_metrics",
+	} {
+		require.Equal(t, spans["app.startup.telemetry"].SpanID(), parents[name].SpanID(), name)
+	}
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	var found bool
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "app.startup.duration" {
+				continue
+			}
+			found = true
+			gauge, ok := m.Data.(metricdata.Gauge[float64])
+			require.True(t, ok)
+			require.Len(t, gauge.DataPoints, 1)
+			require.Positive(t, gauge.DataPoints[0].Value)
+		}
+	}
+	require.True(t, found)
+
+	require.NoError(t, a.Stop(context.Background()))
+}
+
+func TestStartup(t *testing.T) {
+	s := newStartup(time.Now())
+	done := s.phase("outer")
+	s.phase("inner")(errors.New("failed"))
+	done(nil)
+	_, ok := s.duration()
+	require.False(t, ok)
+
+	recorder := tracetest.NewSpanRecorder()
+	s.finish(context.Background(), sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	// Not recorded after finish.
+	s.phase("late")(nil)
+	s.finish(context.Background(), sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	require.Equal(t, "app.startup.outer", spans[0].Name())
+	require.Equal(t, "app.startup.inner", spans[1].Name())
+	require.Equal(t, spans[0].SpanContext().SpanID(), spans[1].Parent().SpanID())
+	require.Equal(t, "failed", spans[1].Status().Description)
+	require.Equal(t, "app.startup", spans[2].Name())
+
+	d, ok := s.duration()
+	require.True(t, ok)
+	require.Positive(t, d)
+
+	var nilStartup *startup
+	nilStartup.phase("noop")(nil)
+	nilStartup.markReady()
+}
@@ -3,9 +3,9 @@ package app
 import (
 	"context"
 	"fmt"
-	"net"
 	"net/http"
 	"os"
+	"strconv"
 	"sync"
 	"time"
 
@@ -13,7 +13,6 @@ import (
 	"github.com/go-logr/zapr"
 	otelpyroscope "github.com/grafana/otel-profiling-go"
 	promClient "github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/contrib/instrumentation/runtime"
 	"go.opentelemetry.io/contrib/propagators/autoprop"
 	"go.opentelemetry.io/otel"
@@ -26,10 +25,10 @@ import (
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 
-	"github.com/go-faster/sdk/autologs"
-	"github.com/go-faster/sdk/autometer"
 	"github.com/go-faster/sdk/autopyro"
-	"github.com/go-faster/sdk/autotracer"
+	"github.com/go-faster/sdk/internal/delegate"
+	"github.com/go-faster/sdk/otelstats"
+	"github.com/go-faster/sdk/tracez"
 )
 
 type httpEndpoint struct {
@@ -37,6 +36,9 @@ type httpEndpoint struct {
 	mux      *http.ServeMux
 	services []string
 	addr     string
+
+	routesMux sync.Mutex
+	routes    []string
 }
 
 // Deprecated: use Telemetry.
@@ -46,23 +48,48 @@ type Metrics = Telemetry
 type Telemetry struct {
 	lg *zap.Logger
 
-	prom *promClient.Registry
-	http []httpEndpoint
+	prom  *promClient.Registry
+	http  []*httpEndpoint
+	admin *httpEndpoint
 
 	tracerProvider  trace.TracerProvider
 	meterProvider   metric.MeterProvider
 	loggerProvider  log.LoggerProvider
+	tracerDelegate  *delegate.TracerProvider
+	meterDelegate   *delegate.MeterProvider
+	loggerDelegate  *delegate.LoggerProvider
 	shutdownContext context.Context
 	baseContext     context.Context
+	shutdownTimeout time.Duration
 
 	resource *resource.Resource
 
 	propagator propagation.TextMapPropagator
-	shutdowns  []shutdown
+
+	level zap.AtomicLevel
+
+	// reload returns re-read options and resource.
+	reload       func(ctx context.Context) (options, *resource.Resource, error)
+	reloadMux    sync.Mutex
+	providers    providers // guarded by reloadMux
+	promHandler  *swapHandler
+	pprofHandler *swapHandler
+
+	tracez *tracez.Processor
+	stats  *otelstats.Stats
+	sd     *sdNotifier
+
+	startup *startup
+
+	hooks         lifecycle
+	phaseTimeouts map[Phase]time.Duration
+
+	health  healthChecks
+	workers workers
 }
 
 // ShutdownContext is context for triggering graceful shutdown.
-// It is cancelled on SIGINT.
+// It is cancelled on shutdown signal (SIGINT or SIGTERM by default).
 //
 // Base context [Telemetry.BaseContext] can be used during shutdown to finish pending operations, it will be cancelled later
 // on timeout.
@@ -75,15 +102,6 @@ func (m *Telemetry) BaseContext() context.Context {
 	return m.baseContext
 }
 
-func (m *Telemetry) registerShutdown(name string, fn func(ctx context.Context) error) {
-	m.shutdowns = append(m.shutdowns, shutdown{name: name, fn: fn})
-}
-
-type shutdown struct {
-	name string
-	fn   func(ctx context.Context) error
-}
-
 func (m *Telemetry) String() string {
 	return "metrics"
 }
@@ -92,8 +110,7 @@ func (m *Telemetry) run(ctx context.Context) error {
 	defer m.lg.Debug("Stopped metrics")
 	wg, ctx := errgroup.WithContext(ctx)
 
-	for i := range m.http {
-		e := m.http[i]
+	for _, e := range m.http {
 		wg.Go(func() error {
 			m.lg.Info("Starting http server",
 				zap.Strings("services", e.services),
@@ -107,21 +124,20 @@ func (m *Telemetry) run(ctx context.Context) error {
 	}
 	wg.Go(func() error {
 		// Wait until g ctx canceled, then try to shut down server.
-		baseCtx := ctx
 		select {
 		case <-ctx.Done():
 			// Non-graceful shutdown.
-			baseCtx = context.Background()
 		case <-m.ShutdownContext().Done():
 			// Graceful shutdown attempt.
 		}
 
 		m.lg.Debug("Shutting down metrics")
-		ctx, cancel := context.WithTimeout(baseCtx, shutdownTimeout)
-		defer cancel()
 
+		// Phases have own shutdown timeout, so shutdown continues even if
+		// base context is cancelled.
+		//
 		// Not returning error, just reporting to log.
-		m.shutdown(ctx)
+		m.shutdown(context.WithoutCancel(ctx))
 
 		return nil
 	})
@@ -129,33 +145,6 @@ func (m *Telemetry) run(ctx context.Context) error {
 	return wg.Wait()
 }
 
-func (m *Telemetry) shutdown(ctx context.Context) {
-	defer m.lg.Debug("Shut down")
-	var wg sync.WaitGroup
-
-	// Launch shutdowns in parallel.
-	wg.Add(len(m.shutdowns))
-
-	var shutdowns []string
-	for _, s := range m.shutdowns {
-		var (
-			f = s.fn
-			n = s.name
-		)
-		shutdowns = append(shutdowns, n)
-		go func() {
-			defer wg.Done()
-			if err := f(ctx); err != nil {
-				m.lg.Error("Failed to shutdown", zap.Error(err), zap.String("name", n))
-			}
-		}()
-	}
-
-	// Wait for all shutdowns to finish.
-	m.lg.Info("Waiting for shutdowns", zap.Strings("shutdowns", shutdowns))
-	wg.Wait()
-}
-
 func (m *Telemetry) MeterProvider() metric.MeterProvider {
 	if m.meterProvider == nil {
 		return otel.GetMeterProvider()
@@ -181,162 +170,178 @@ func (m *Telemetry) TextMapPropagator() propagation.TextMapPropagator {
 	return m.propagator
 }
 
-func prometheusAddr() string {
-	host := "localhost"
-	port := "9464"
-	if v := os.Getenv("OTEL_EXPORTER_PROMETHEUS_HOST"); v != "" {
-		host = v
+// registerEndpoint returns http endpoint for addr, reusing existing
+// endpoint if addresses match.
+func (m *Telemetry) registerEndpoint(addr, service string) *httpEndpoint {
+	for _, e := range m.http {
+		if e.addr != addr {
+			continue
+		}
+		// Using existing endpoint.
+		e.services = append(e.services, service)
+		return e
 	}
-	if v := os.Getenv("OTEL_EXPORTER_PROMETHEUS_PORT"); v != "" {
-		port = v
+	// Creating new endpoint.
+	mux := http.NewServeMux()
+	e := &httpEndpoint{
+		srv:      &http.Server{Addr: addr, Handler: mux},
+		addr:     addr,
+		mux:      mux,
+		services: []string{service},
 	}
-	return net.JoinHostPort(host, port)
-}
-
-type zapErrorHandler struct {
-	lg *zap.Logger
-}
-
-func (z zapErrorHandler) Handle(err error) {
-	z.lg.Error("Error", zap.Error(err))
+	m.http = append(m.http, e)
+	return e
 }
 
 func newTelemetry(
 	baseCtx, shutdownCtx context.Context,
 	lg *zap.Logger,
 	res *resource.Resource,
-	meterOptions []autometer.Option,
-	tracerOptions []autotracer.Option,
-	logsOptions []autologs.Option,
+	opts options,
+	st *startup,
 ) (*Telemetry, error) {
-	{
+	if opts.globalState {
 		// Setup global OTEL logger and error handler.
 		logger := lg.Named("otel")
 		otel.SetLogger(zapr.NewLogger(logger))
-		otel.SetErrorHandler(zapErrorHandler{lg: logger})
+		otel.SetErrorHandler(newZapErrorHandler(logger, errorLogInterval))
 	}
 	m := &Telemetry{
 		lg:       lg,
 		resource: res,
+		level:    opts.zapConfig.Level,
 
 		shutdownContext: shutdownCtx,
 		baseContext:     baseCtx,
+		shutdownTimeout: opts.shutdownTimeout,
+		phaseTimeouts:   opts.phaseTimeouts,
+
+		stats:   otelstats.New(),
+		startup: st,
 	}
-	ctx := baseCtx
-	{
-		provider, stop, err := autologs.NewLoggerProvider(ctx,
-			include(logsOptions,
-				autologs.WithResource(res),
-			)...,
-		)
-		if err != nil {
-			return nil, errors.Wrap(err, "logger provider")
+	// Recording spans for /debug/tracez if enabled and debug handlers are
+	// served. Opt-in, as it makes tracer provider record all spans.
+	if os.Getenv("ADMIN_ADDR") != "" || os.Getenv("PPROF_ADDR") != "" {
+		if v, _ := strconv.ParseBool(os.Getenv("TRACEZ")); v {
+			m.tracez = tracez.NewProcessor(tracez.Options{})
 		}
-		m.loggerProvider = provider
-		m.registerShutdown("logger", stop)
 	}
-	{
-		provider, stop, err := autotracer.NewTracerProvider(ctx,
-			include(tracerOptions,
-				autotracer.WithResource(res),
-			)...,
-		)
-		if err != nil {
-			return nil, errors.Wrap(err, "tracer provider")
-		}
-		m.tracerProvider = provider
-		m.registerShutdown("tracer", stop)
+	ctx := baseCtx
+	p, err := m.newProviders(ctx, res, opts)
+	if err != nil {
+		return nil, err
 	}
-	{
-		provider, stop, err := autometer.NewMeterProvider(ctx,
-			include(meterOptions,
-				autometer.WithResource(res),
-				autometer.WithOnPrometheusRegistry(func(reg *promClient.Registry) {
-					m.prom = reg
-				}),
-			)...,
-		)
+	m.providers = p
+	m.prom = p.prom
+	// Providers are replaced behind delegates on reload.
+	m.tracerDelegate = delegate.NewTracerProvider(p.tracer)
+	m.meterDelegate = delegate.NewMeterProvider(p.meter)
+	m.loggerDelegate = delegate.NewLoggerProvider(p.logger)
+	m.tracerProvider = m.tracerDelegate
+	m.meterProvider = m.meterDelegate
+	m.loggerProvider = m.loggerDelegate
+	m.OnStop(PhaseLogs, "logger", m.stopHook(func(p providers) Hook { return p.loggerStop }))
+	m.OnStop(PhaseTelemetry, "tracer", m.stopHook(func(p providers) Hook { return p.tracerStop }))
+	m.OnStop(PhaseTelemetry, "meter", m.stopHook(func(p providers) Hook { return p.meterStop }))
+
+	// Automatically composited from the OTEL_PROPAGATORS environment variable,
+	// unless set by configuration file.
+	m.propagator = autoprop.NewTextMapPropagator()
+	if c := opts.config; c != nil {
+		p, ok, err := c.TextMapPropagator()
 		if err != nil {
-			return nil, errors.Wrap(err, "meter provider")
+			return nil, errors.Wrap(err, "propagator")
+		}
+		if ok {
+			m.propagator = p
 		}
-		m.meterProvider = provider
-		m.registerShutdown("meter", stop)
 	}
 
-	// Automatically composited from the OTEL_PROPAGATORS environment variable.
-	m.propagator = autoprop.NewTextMapPropagator()
+	// Exporter statistics are reported by current meter provider.
+	if err := m.stats.Register(m.MeterProvider()); err != nil {
+		return nil, errors.Wrap(err, "exporter stats")
+	}
 
 	// Setting up go runtime metrics.
-	if err := runtime.Start(
+	done := st.phase("runtime_metrics")
+	err = runtime.Start(
 		runtime.WithMeterProvider(m.MeterProvider()),
 		runtime.WithMinimumReadMemStatsInterval(time.Second), // export as env?
-	); err != nil {
+	)
+	done(err)
+	if err != nil {
 		return nil, errors.Wrap(err, "runtime metrics")
 	}
 
 	// Setup pyroscope.
 	if autopyro.Enabled() {
+		done := st.phase("pyroscope")
 		stop, err := autopyro.Setup(ctx)
+		done(err)
 		if err != nil {
 			return nil, errors.Wrap(err, "pyroscope")
 		}
-		m.registerShutdown("pyroscope", stop)
+		m.OnStop(PhaseTelemetry, "pyroscope", Hook(stop))
 		// Setup pyroscope tracing integration.
 		// See https://github.com/grafana/otel-profiling-go
 		m.tracerProvider = otelpyroscope.NewTracerProvider(m.tracerProvider)
 	}
 
-	// Register global OTEL providers.
-	otel.SetMeterProvider(m.MeterProvider())
-	otel.SetTracerProvider(m.TracerProvider())
-	otel.SetTextMapPropagator(m.TextMapPropagator())
+	if opts.globalState {
+		// Register global OTEL providers.
+		otel.SetMeterProvider(m.MeterProvider())
+		otel.SetTracerProvider(m.TracerProvider())
+		otel.SetTextMapPropagator(m.TextMapPropagator())
+	}
 
 	// Initialize and register HTTP servers if required.
 	//
+	// Admin endpoint hosts all built-in services.
+	if v := os.Getenv("ADMIN_ADDR"); v != "" {
+		m.admin = m.registerEndpoint(v, "admin")
+		m.admin.handle("/{$}", m.admin.indexHandler())
+	}
 	// Adding prometheus.
 	if m.prom != nil {
+		done := st.phase("prometheus")
 		promAddr := prometheusAddr()
 		if v := os.Getenv("METRICS_ADDR"); v != "" {
 			promAddr = v
 		}
-		mux := http.NewServeMux()
-		e := httpEndpoint{
-			srv:      &http.Server{Addr: promAddr, Handler: mux},
-			services: []string{"prometheus"},
-			addr:     promAddr,
-			mux:      mux,
+		if c := opts.config; c != nil {
+			if v, ok := c.PrometheusAddr(); ok {
+				promAddr = v
+			}
 		}
-		mux.Handle("/metrics",
-			promhttp.HandlerFor(m.prom, promhttp.HandlerOpts{}),
-		)
-		m.http = append(m.http, e)
+		m.promHandler = newSwapHandler(newPrometheusHandler(lg, m.prom))
+		m.mount(m.registerEndpoint(promAddr, "prometheus"), "/metrics", m.promHandler)
+		done(nil)
 	}
-	// Adding pprof.
-	if v := os.Getenv("PPROF_ADDR"); v != "" {
-		const serviceName = "pprof"
-		// Search for existing endpoint.
-		var he httpEndpoint
-		for i, e := range m.http {
-			if e.addr != v {
-				continue
-			}
-			// Using existing endpoint
-			he = e
-			he.services = append(he.services, serviceName)
-			m.http[i] = he
+	// Adding pprof and other debug handlers.
+	{
+		var e *httpEndpoint
+		if v := os.Getenv("PPROF_ADDR"); v != "" {
+			e = m.registerEndpoint(v, "pprof")
 		}
-		if he.srv == nil {
-			// Creating new endpoint.
-			mux := http.NewServeMux()
-			he = httpEndpoint{
-				srv:      &http.Server{Addr: v, Handler: mux},
-				addr:     v,
-				mux:      mux,
-				services: []string{serviceName},
+		if e != nil || m.admin != nil {
+			done := st.phase("pprof")
+			m.pprofHandler = newSwapHandler(m.newProfiler())
+			m.mount(e, "/debug/pprof/", m.pprofHandler)
+			m.mount(e, "/debug/loglevel", newLogLevelHandler(lg, m.level))
+			m.mount(e, "/debug/reload", m.reloadHandler())
+			if m.tracez != nil {
+				m.mount(e, "/debug/tracez", tracez.NewHandler(m.tracez))
 			}
-			m.http = append(m.http, he)
+			done(nil)
 		}
-		m.registerProfiler(he.mux)
+	}
+	// Adding health checks.
+	{
+		var e *httpEndpoint
+		if v := os.Getenv("HEALTH_ADDR"); v != "" {
+			e = m.registerEndpoint(v, "health")
+		}
+		m.registerHealth(e)
 	}
 	fields := []zap.Field{
 		zap.Stringer("otel.resource", res),
@@ -346,7 +351,13 @@ func newTelemetry(
 			fields = append(fields, zap.String("http."+s, e.addr))
 		}
 		name := fmt.Sprintf("http %v", e.services)
-		m.registerShutdown(name, e.srv.Shutdown)
+		m.OnStop(PhaseServers, name, e.srv.Shutdown)
+	}
+	// Connecting after last fallible step, so socket is not leaked on error.
+	if sd, err := newSDNotifier(os.Getenv("NOTIFY_SOCKET")); err != nil {
+		lg.Warn("Failed to connect to systemd notification socket", zap.Error(err))
+	} else {
+		m.sd = sd
 	}
 	lg.Info("Metrics initialized", fields...)
 	return m, nil
@@ -0,0 +1,306 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/go-faster/sdk/zctx"
+)
+
+const instrumentationName = "github.com/go-faster/sdk/app"
+
+const (
+	defaultWorkerBackoffInitial = time.Millisecond * 100
+	defaultWorkerBackoffMax     = time.Second * 30
+)
+
+type workerOptions struct {
+	restart        bool
+	backoffInitial time.Duration
+	backoffMax     time.Duration
+	maxRestarts    int
+}
+
+// WorkerOption is a functional option for worker started by [Telemetry.Go].
+type WorkerOption interface {
+	apply(o *workerOptions)
+}
+
+type workerOptionFunc func(*workerOptions)
+
+func (f workerOptionFunc) apply(o *workerOptions) {
+	f(o)
+}
+
+// WithWorkerRestart enables worker restart on failure with exponential backoff
+// from initial to max delay.
+//
+// Zero values mean 100ms and 30s respectively.
+func WithWorkerRestart(initial, max time.Duration) WorkerOption {
+	return workerOptionFunc(func(o *workerOptions) {
+		o.restart = true
+		if initial > 0 {
+			o.backoffInitial = initial
+		}
+		if max > 0 {
+			o.backoffMax = max
+		}
+	})
+}
+
+// WithWorkerMaxRestarts limits count of worker restarts, zero means no limit.
+//
+// Worker error is returned after limit is reached.
+func WithWorkerMaxRestarts(n int) WorkerOption {
+	return workerOptionFunc(func(o *workerOptions) {
+		o.restart = true
+		o.maxRestarts = n
+	})
+}
+
+// worker is a running worker, used to report uptime.
+type worker struct {
+	name  string
+	mux   sync.Mutex
+	start time.Time // zero if not running
+}
+
+func (w *worker) setStart(t time.Time) {
+	w.mux.Lock()
+	w.start = t
+	w.mux.Unlock()
+}
+
+func (w *worker) uptime(now time.Time) time.Duration {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	if w.start.IsZero() {
+		return 0
+	}
+	return now.Sub(w.start)
+}
+
+// workers supervises background workers of application.
+type workers struct {
+	mux     sync.Mutex
+	group   *errgroup.Group // nil until application start
+	pending []func() error
+	closed  bool
+	running map[*worker]struct{}
+	wg      sync.WaitGroup
+
+	metricsOnce sync.Once
+	restarts    metric.Int64Counter
+	panics      metric.Int64Counter
+}
+
+// start launches pending workers in application group.
+func (w *workers) start(g *errgroup.Group) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	w.group = g
+	for _, f := range w.pending {
+		g.Go(f)
+	}
+	w.pending = nil
+}
+
+// close prevents new workers from starting.
+func (w *workers) close() {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	w.closed = true
+}
+
+// wait waits for all workers to stop.
+func (w *workers) wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *workers) observe(_ context.Context, o metric.Observer, uptime metric.Float64ObservableGauge) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	now := time.Now()
+	for r := range w.running {
+		o.ObserveFloat64(uptime, r.uptime(now).Seconds(),
+			metric.WithAttributes(attribute.String("worker", r.name)),
+		)
+	}
+}
+
+func (m *Telemetry) initWorkerMetrics() {
+	w := &m.workers
+	w.metricsOnce.Do(func() {
+		meter := m.MeterProvider().Meter(instrumentationName)
+		var err error
+		if w.restarts, err = meter.Int64Counter("app.worker.restarts",
+			metric.WithDescription("Number of worker restarts after failure"),
+			metric.WithUnit("{restart}"),
+		); err != nil {
+			m.lg.Warn("Failed to create worker metric", zap.Error(err))
+		}
+		if w.panics, err = meter.Int64Counter("app.worker.panics",
+			metric.WithDescription("Number of recovered worker panics"),
+			metric.WithUnit("{panic}"),
+		); err != nil {
+			m.lg.Warn("Failed to create worker metric", zap.Error(err))
+		}
+		uptime, err := meter.Float64ObservableGauge("app.worker.uptime",
+			metric.WithDescription("Time since current worker run started"),
+			metric.WithUnit("s"),
+		)
+		if err != nil {
+			m.lg.Warn("Failed to create worker metric", zap.Error(err))
+			return
+		}
+		if _, err := meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+			w.observe(ctx, o, uptime)
+			return nil
+		}, uptime); err != nil {
+			m.lg.Warn("Failed to register worker metric callback", zap.Error(err))
+		}
+	})
+}
+
+// Go starts supervised background worker f.
+//
+// Worker context is [Telemetry.ShutdownContext] with named logger and span, so
+// worker should stop on its cancellation; returned context error is considered
+// graceful. Worker panics are recovered and reported as errors.
+//
+// Failed worker fails whole application, unless restart is enabled by [WithWorkerRestart].
+// Workers are awaited during [PhaseApplication] shutdown phase.
+//
+// Workers started before [App.Start] are launched on start.
+func (m *Telemetry) Go(name string, f func(ctx context.Context) error, opts ...WorkerOption) {
+	o := workerOptions{
+		backoffInitial: defaultWorkerBackoffInitial,
+		backoffMax:     defaultWorkerBackoffMax,
+	}
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+	m.initWorkerMetrics()
+
+	w := &m.workers
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	if w.closed {
+		m.lg.Warn("Worker started after application stop, ignoring", zap.String("worker", name))
+		return
+	}
+	w.wg.Add(1)
+	run := func() error {
+		defer w.wg.Done()
+		return m.superviseWorker(name, f, o)
+	}
+	if w.group == nil {
+		w.pending = append(w.pending, run)
+		return
+	}
+	w.group.Go(run)
+}
+
+// superviseWorker runs worker, restarting it on failure if enabled.
+func (m *Telemetry) superviseWorker(name string, f func(ctx context.Context) error, o workerOptions) error {
+	var (
+		w       = &m.workers
+		r       = &worker{name: name}
+		attrs   = metric.WithAttributes(attribute.String("worker", name))
+		backoff = o.backoffInitial
+		ctx     = m.ShutdownContext()
+	)
+	lg := zctx.From(ctx).Named(name).With(zap.String("worker", name))
+	ctx = zctx.Base(ctx, lg)
+
+	w.mux.Lock()
+	if w.running == nil {
+		w.running = make(map[*worker]struct{})
+	}
+	w.running[r] = struct{}{}
+	w.mux.Unlock()
+	defer func() {
+		w.mux.Lock()
+		delete(w.running, r)
+		w.mux.Unlock()
+	}()
+
+	for restarts := 0; ; restarts++ {
+		start := time.Now()
+		r.setStart(start)
+		err := m.runWorker(ctx, name, f)
+		r.setStart(time.Time{})
+		if err == nil {
+			lg.Debug("Worker stopped")
+			return nil
+		}
+		if errors.Is(err, ctx.Err()) {
+			// Parent context got cancelled, error is expected.
+			lg.Debug("Worker gracefully stopped")
+			return nil
+		}
+		if ctx.Err() != nil || !o.restart || (o.maxRestarts > 0 && restarts >= o.maxRestarts) {
+			return errors.Wrapf(err, "worker %q", name)
+		}
+		if time.Since(start) > o.backoffMax {
+			// Worker was running long enough, resetting backoff.
+			backoff = o.backoffInitial
+		}
+		lg.Warn("Worker failed, restarting",
+			zap.Error(err),
+			zap.Duration("backoff", backoff),
+			zap.Int("restarts", restarts),
+		)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, o.backoffMax)
+		if w.restarts != nil {
+			w.restarts.Add(ctx, 1, attrs)
+		}
+	}
+}
+
+// runWorker runs single worker attempt in span, recovering panic.
+func (m *Telemetry) runWorker(ctx context.Context, name string, f func(ctx context.Context) error) (rerr error) {
+	ctx, span := m.TracerProvider().Tracer(instrumentationName).Start(ctx, "worker."+name)
+	defer func() {
+		if ec := recover(); ec != nil {
+			zctx.From(ctx).Error("Worker panic",
+				zap.String("panic", fmt.Sprintf("%v", ec)),
+				zap.StackSkip("stack", 1),
+			)
+			if p := m.workers.panics; p != nil {
+				p.Add(ctx, 1, metric.WithAttributes(attribute.String("worker", name)))
+			}
+			rerr = panicError(ec)
+		}
+		if rerr != nil {
+			span.RecordError(rerr)
+			span.SetStatus(codes.Error, rerr.Error())
+		}
+		span.End()
+	}()
+	return f(ctx)
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+
+	"github.com/go-faster/sdk/autometer"
+	"github.com/go-faster/sdk/zctx"
+)
+
+func TestTelemetry_Go(t *testing.T) {
+	t.Run("Restart", func(t *testing.T) {
+		setupTestEnv(t)
+		t.Setenv("OTEL_METRICS_EXPORTER", "manual")
+		reader := sdkmetric.NewManualReader()
+
+		var attempts atomic.Int32
+		recovered := make(chan struct{})
+		a, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, testOptions(
+			WithMeterOptions(autometer.WithLookupExporter(func(ctx context.Context, name string) (sdkmetric.Reader, bool, error) {
+				return reader, true, nil
+			})),
+		)...)
+		require.NoError(t, err)
+
+		// Started before application start.
+		a.Telemetry().Go("flaky", func(ctx context.Context) error {
+			zctx.From(ctx).Info("Running")
+			switch attempts.Add(1) {
+			case 1:
+				return errors.New("failed")
+			case 2:
+				panic("boom")
+			case 3:
+				close(recovered)
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		}, WithWorkerRestart(time.Millisecond, time.Millisecond*5))
+
+		require.NoError(t, a.Start(context.Background()))
+		<-recovered
+
+		var rm metricdata.ResourceMetrics
+		require.NoError(t, reader.Collect(context.Background(), &rm))
+		values := map[string]bool{}
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				values[m.Name] = true
+			}
+		}
+		require.True(t, values["app.worker.restarts"])
+		require.True(t, values["app.worker.panics"])
+		require.True(t, values["app.worker.uptime"])
+
+		require.NoError(t, a.Stop(context.Background()))
+		require.Equal(t, int32(3), attempts.Load())
+	})
+	t.Run("Fail", func(t *testing.T) {
+		setupTestEnv(t)
+		testErr := errors.New("test error")
+		a, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+			m.Go("failing", func(ctx context.Context) error {
+				return testErr
+			}, WithWorkerMaxRestarts(2), WithWorkerRestart(time.Millisecond, time.Millisecond))
+			<-ctx.Done()
+			return ctx.Err()
+		}, testOptions()...)
+		require.NoError(t, err)
+		require.NoError(t, a.Start(context.Background()))
+		err = a.Wait()
+		require.ErrorIs(t, err, testErr)
+		require.ErrorContains(t, err, `worker "failing"`)
+	})
+	t.Run("Drain", func(t *testing.T) {
+		setupTestEnv(t)
+		var drained atomic.Bool
+		a, err := New(func(ctx context.Context, lg *zap.Logger, m *Telemetry) error {
+			return nil
+		}, testOptions()...)
+		require.NoError(t, err)
+		a.Telemetry().Go("slow", func(ctx context.Context) error {
+			<-ctx.Done()
+			time.Sleep(time.Millisecond * 10)
+			drained.Store(true)
+			return ctx.Err()
+		})
+		require.NoError(t, a.Start(context.Background()))
+		require.NoError(t, a.Wait())
+		require.True(t, drained.Load())
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/go-faster/errors"
@@ -16,6 +17,8 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	"github.com/go-faster/sdk/internal/otlpjson"
+	"github.com/go-faster/sdk/otelconfig"
 	"github.com/go-faster/sdk/zctx"
 )
 
@@ -25,6 +28,7 @@ const (
 
 	protoHTTP         = "http"
 	protoHTTPProtobuf = "http/protobuf"
+	protoHTTPJSON     = "http/json"
 	protoGRPC         = "grpc"
 	defaultProto      = protoGRPC
 )
@@ -70,17 +74,75 @@ func NewLoggerProvider(ctx context.Context, options ...Option) (
 		logOptions = append(logOptions, sdklog.WithResource(cfg.res))
 	}
 
-	ret := func(e sdklog.Exporter) (log.LoggerProvider, func(ctx context.Context) error, error) {
+	level := cfg.level
+	if level == nil {
+		// Core level is dynamic if logger is built with zap.AtomicLevel.
+		level = lg.Core()
+	}
+	if cfg.file == nil {
+		if cfg.file, err = otelconfig.FromEnv(); err != nil {
+			return nil, nil, errors.Wrap(err, "load config")
+		}
+	}
+	if cfg.file != nil {
+		lg.Debug("Using declarative configuration")
+		return newFromConfig(ctx, cfg, cfg.file, level)
+	}
+
+	exporters := exporterNames(lg, getEnvOr("OTEL_LOGS_EXPORTER", expOTLP))
+	if len(exporters) == 0 {
+		lg.Debug("Using no-op logs exporter")
+		return noop.NewLoggerProvider(), nop, nil
+	}
+	var created []sdklog.Exporter
+	for _, exporter := range exporters {
+		e, err := newExporter(ctx, cfg, exporter)
+		if err != nil {
+			// Releasing already created exporters.
+			for _, e := range created {
+				_ = e.Shutdown(ctx)
+			}
+			return nil, nil, err
+		}
+		created = append(created, e)
 		logOptions = append(logOptions,
 			sdklog.WithProcessor(&levelFilterProcessor{
-				next:     sdklog.NewBatchProcessor(e),
-				severity: zapLevelToOTelSeverity(lg.Level()),
+				next:  cfg.stats.BatchLogProcessor(exporter, e, 0),
+				level: level,
 			}),
 		)
-		provider := sdklog.NewLoggerProvider(logOptions...)
-		return provider, provider.Shutdown, nil
 	}
-	exporter := strings.TrimSpace(getEnvOr("OTEL_LOGS_EXPORTER", expOTLP))
+	provider := sdklog.NewLoggerProvider(logOptions...)
+	return provider, provider.Shutdown, nil
+}
+
+// exporterNames returns exporter names from comma-separated list.
+//
+// Empty entries and duplicates are skipped. As per specification, "none"
+// is ignored if other exporters are set. Empty result means no exporters.
+func exporterNames(lg *zap.Logger, v string) []string {
+	var (
+		names []string
+		none  bool
+	)
+	for name := range strings.SplitSeq(v, ",") {
+		switch name = strings.TrimSpace(name); {
+		case name == "" || slices.Contains(names, name):
+		case name == expNone:
+			none = true
+		default:
+			names = append(names, name)
+		}
+	}
+	if none && len(names) > 0 {
+		lg.Warn("Ignoring logs exporter \"none\" mixed with other exporters", zap.Strings("exporters", names))
+	}
+	return names
+}
+
+// newExporter creates log exporter by name.
+func newExporter(ctx context.Context, cfg config, exporter string) (sdklog.Exporter, error) {
+	lg := zctx.From(ctx)
 	switch exporter {
 	case expOTLP:
 		proto := os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
@@ -92,20 +154,28 @@ func NewLoggerProvider(ctx context.Context, options ...Option) (
 		}
 		lg.Debug("Using OTLP logs exporter", zap.String("protocol", proto))
 		switch proto {
-		case protoHTTP, protoHTTPProtobuf:
-			exp, err := otlploghttp.New(ctx)
+		case protoHTTP, protoHTTPProtobuf, protoHTTPJSON:
+			var opts []otlploghttp.Option
+			if proto == protoHTTPJSON {
+				client, err := otlpjson.ConfigFromEnv(otlpjson.Logs)
+				if err != nil {
+					return nil, errors.Wrap(err, "configure OTLP JSON client")
+				}
+				opts = append(opts, otlploghttp.WithHTTPClient(otlpjson.NewClient(otlpjson.Logs, client)))
+			}
+			exp, err := otlploghttp.New(ctx, opts...)
 			if err != nil {
-				return nil, nil, errors.Wrap(err, "create OTLP HTTP logs exporter")
+				return nil, errors.Wrap(err, "create OTLP HTTP logs exporter")
 			}
-			return ret(exp)
+			return exp, nil
 		case protoGRPC:
 			exp, err := otlploggrpc.New(ctx)
 			if err != nil {
-				return nil, nil, errors.Wrap(err, "create OTLP gRPC logs exporter")
+				return nil, errors.Wrap(err, "create OTLP gRPC logs exporter")
 			}
-			return ret(exp)
+			return exp, nil
 		default:
-			return nil, nil, errors.Errorf("unsupported logs otlp protocol %q", proto)
+			return nil, errors.Errorf("unsupported logs otlp protocol %q", proto)
 		}
 	case writerStdout, writerStderr:
 		lg.Debug("Using stdout log exporter", zap.String("writer", exporter))
@@ -115,12 +185,9 @@ func NewLoggerProvider(ctx context.Context, options ...Option) (
 		}
 		exp, err := stdoutlog.New(stdoutlog.WithWriter(writer))
 		if err != nil {
-			return nil, nil, errors.Wrapf(err, "create %q logs exporter", exporter)
+			return nil, errors.Wrapf(err, "create %q logs exporter", exporter)
 		}
-		return ret(exp)
-	case expNone:
-		lg.Debug("Using no-op logs exporter")
-		return noop.NewLoggerProvider(), nop, nil
+		return exp, nil
 	default:
 		lookup := cfg.lookup
 		if lookup == nil {
@@ -129,31 +196,34 @@ func NewLoggerProvider(ctx context.Context, options ...Option) (
 		lg.Debug("Looking for logs exporter", zap.String("exporter", exporter))
 		exp, ok, err := lookup(ctx, exporter)
 		if err != nil {
-			return nil, nil, errors.Wrapf(err, "create %q", exporter)
+			return nil, errors.Wrapf(err, "create %q", exporter)
 		}
 		if !ok {
 			break
 		}
 
 		lg.Debug("Using user-defined log exporter", zap.String("exporter", exporter))
-		return ret(exp)
+		return exp, nil
 	}
-	return nil, nil, errors.Errorf("unsupported OTEL_LOGS_EXPORTER %q", exporter)
+	return nil, errors.Errorf("unsupported OTEL_LOGS_EXPORTER %q", exporter)
 }
 
 // levelFilterProcessor implements level filtering, since otlplog does not.
 //
 // Fuck you too, OpenTelemetry.
 type levelFilterProcessor struct {
-	next     sdklog.Processor
-	severity log.Severity
+	next  sdklog.Processor
+	level zapcore.LevelEnabler
 }
 
 var _ sdklog.Processor = (*levelFilterProcessor)(nil)
 
 // Enabled implements [sdklog.FilterProcessor].
 func (l *levelFilterProcessor) Enabled(ctx context.Context, param sdklog.EnabledParameters) bool {
-	return param.Severity >= l.severity
+	if param.Severity == log.SeverityUndefined {
+		return false
+	}
+	return l.level.Enabled(otelSeverityToZapLevel(param.Severity))
 }
 
 // OnEmit implements [sdklog.Processor].
@@ -171,23 +241,21 @@ func (l *levelFilterProcessor) Shutdown(ctx context.Context) error {
 	return l.next.Shutdown(ctx)
 }
 
-func zapLevelToOTelSeverity(level zapcore.Level) log.Severity {
-	switch level {
-	case zapcore.DebugLevel:
-		return log.SeverityDebug
-	case zapcore.InfoLevel:
-		return log.SeverityInfo
-	case zapcore.WarnLevel:
-		return log.SeverityWarn
-	case zapcore.ErrorLevel:
-		return log.SeverityError
-	case zapcore.DPanicLevel:
-		return log.SeverityFatal1
-	case zapcore.PanicLevel:
-		return log.SeverityFatal2
-	case zapcore.FatalLevel:
-		return log.SeverityFatal3
+func otelSeverityToZapLevel(severity log.Severity) zapcore.Level {
+	switch {
+	case severity < log.SeverityInfo:
+		return zapcore.DebugLevel
+	case severity < log.SeverityWarn:
+		return zapcore.InfoLevel
+	case severity < log.SeverityError:
+		return zapcore.WarnLevel
+	case severity < log.SeverityFatal1:
+		return zapcore.ErrorLevel
+	case severity < log.SeverityFatal2:
+		return zapcore.DPanicLevel
+	case severity < log.SeverityFatal3:
+		return zapcore.PanicLevel
 	default:
-		return log.SeverityUndefined
+		return zapcore.FatalLevel
 	}
 }
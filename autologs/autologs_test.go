@@ -2,6 +2,9 @@ package autologs_test
 
 import (
 	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"slices"
 	"sync"
 	"sync/atomic"
@@ -9,8 +12,11 @@ import (
 
 	"github.com/go-faster/errors"
 	"github.com/go-faster/sdk/autologs"
+	"github.com/go-faster/sdk/otelconfig"
 	"github.com/go-faster/sdk/zctx"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 	"go.opentelemetry.io/contrib/bridges/otelzap"
 	sdklog "go.opentelemetry.io/otel/sdk/log"
 	"go.uber.org/zap"
@@ -59,6 +65,136 @@ func TestNewLoggerProviderLevel(t *testing.T) {
 	)
 }
 
+func TestNewLoggerProviderDynamicLevel(t *testing.T) {
+	ctx := context.Background()
+	const testExporterName = "amongus"
+	t.Setenv("OTEL_LOGS_EXPORTER", testExporterName)
+
+	level := zap.NewAtomicLevelAt(zap.InfoLevel)
+	exporter := &testLogExporter{}
+	provider, shutdown, err := autologs.NewLoggerProvider(ctx,
+		autologs.WithLevel(level),
+		autologs.WithLookupExporter(func(ctx context.Context, name string) (sdklog.Exporter, bool, error) {
+			return exporter, true, nil
+		}),
+	)
+	require.NoError(t, err)
+
+	otelLg := zap.New(otelzap.NewCore("github.com/go-faster/sdk/app",
+		otelzap.WithLoggerProvider(provider),
+	))
+	otelLg.Debug("first debug")
+	level.SetLevel(zap.DebugLevel)
+	otelLg.Debug("second debug")
+	level.SetLevel(zap.ErrorLevel)
+	otelLg.Warn("warning")
+	otelLg.Error("error")
+
+	require.NoError(t, shutdown(ctx))
+
+	var msgs []string
+	for _, r := range exporter.Records() {
+		msgs = append(msgs, r.Body().AsString())
+	}
+	require.Equal(t, []string{"second debug", "error"}, msgs)
+}
+
+func TestNewLoggerProviderConfig(t *testing.T) {
+	ctx := context.Background()
+	c, err := otelconfig.Parse([]byte(`file_format: "0.3"
+logger_provider:
+  processors:
+    - simple:
+        exporter:
+          amongus: {}
+`))
+	require.NoError(t, err)
+
+	exporter := &testLogExporter{}
+	provider, shutdown, err := autologs.NewLoggerProvider(ctx,
+		autologs.WithConfig(c),
+		autologs.WithLevel(zap.InfoLevel),
+		autologs.WithLookupExporter(func(ctx context.Context, name string) (sdklog.Exporter, bool, error) {
+			return exporter, name == "amongus", nil
+		}),
+	)
+	require.NoError(t, err)
+
+	otelLg := zap.New(otelzap.NewCore("github.com/go-faster/sdk/app",
+		otelzap.WithLoggerProvider(provider),
+	))
+	otelLg.Debug("debug")
+	otelLg.Info("information")
+	require.NoError(t, shutdown(ctx))
+
+	var msgs []string
+	for _, r := range exporter.Records() {
+		msgs = append(msgs, r.Body().AsString())
+	}
+	require.Equal(t, []string{"information"}, msgs)
+}
+
+func TestNewLoggerProviderMultipleExporters(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("OTEL_LOGS_EXPORTER", "first,none,second")
+
+	exporters := map[string]*testLogExporter{
+		"first":  {},
+		"second": {},
+	}
+	provider, shutdown, err := autologs.NewLoggerProvider(ctx,
+		autologs.WithLevel(zap.InfoLevel),
+		autologs.WithLookupExporter(func(ctx context.Context, name string) (sdklog.Exporter, bool, error) {
+			e, ok := exporters[name]
+			if !ok {
+				return nil, false, nil
+			}
+			return e, true, nil
+		}),
+	)
+	require.NoError(t, err)
+
+	otelLg := zap.New(otelzap.NewCore("github.com/go-faster/sdk/app",
+		otelzap.WithLoggerProvider(provider),
+	))
+	otelLg.Info("information")
+	require.NoError(t, shutdown(ctx))
+
+	for name, e := range exporters {
+		require.Len(t, e.Records(), 1, name)
+		require.True(t, e.shutdown.Load(), name)
+	}
+}
+
+func TestNewLoggerProviderHTTPJSON(t *testing.T) {
+	ctx := context.Background()
+	received := make(chan plog.Logs, 1)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/logs", r.URL.Path)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		req := plogotlp.NewExportRequest()
+		require.NoError(t, req.UnmarshalJSON(data))
+		received <- req.Logs()
+	}))
+	t.Cleanup(s.Close)
+	t.Setenv("OTEL_LOGS_EXPORTER", "otlp")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "http/json")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", s.URL)
+
+	provider, shutdown, err := autologs.NewLoggerProvider(ctx, autologs.WithLevel(zap.InfoLevel))
+	require.NoError(t, err)
+	otelLg := zap.New(otelzap.NewCore("github.com/go-faster/sdk/app",
+		otelzap.WithLoggerProvider(provider),
+	))
+	otelLg.Info("information")
+	require.NoError(t, shutdown(ctx))
+
+	ld := <-received
+	require.Equal(t, "information", ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().AsString())
+}
+
 type testLogExporter struct {
 	records    []sdklog.Record
 	recordsMux sync.Mutex
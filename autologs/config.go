@@ -6,6 +6,10 @@ import (
 
 	sdklog "go.opentelemetry.io/otel/sdk/log"
 	"go.opentelemetry.io/otel/sdk/resource"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/go-faster/sdk/otelconfig"
+	"github.com/go-faster/sdk/otelstats"
 )
 
 // config contains configuration options for a LoggerProvider.
@@ -13,6 +17,9 @@ type config struct {
 	res    *resource.Resource
 	writer io.Writer
 	lookup LookupExporter
+	level  zapcore.LevelEnabler
+	file   *otelconfig.Config
+	stats  *otelstats.Stats
 }
 
 // newConfig returns a config configured with options.
@@ -68,3 +75,35 @@ func WithLookupExporter(lookup LookupExporter) Option {
 		return conf
 	})
 }
+
+// WithLevel sets level that is used to filter emitted log records.
+//
+// Level is checked on each record, so [zap.AtomicLevel] can be used to change
+// it at runtime. Defaults to level of logger from context.
+func WithLevel(level zapcore.LevelEnabler) Option {
+	return optionFunc(func(conf config) config {
+		conf.level = level
+		return conf
+	})
+}
+
+// WithConfig sets declarative configuration that is used instead of
+// environment variables.
+//
+// By default, configuration is loaded from file set by OTEL_CONFIG_FILE
+// environment variable, if any. Exporters that are not known are created
+// by [LookupExporter].
+func WithConfig(c *otelconfig.Config) Option {
+	return optionFunc(func(conf config) config {
+		conf.file = c
+		return conf
+	})
+}
+
+// WithStats sets statistics collector of exporters and batch processors.
+func WithStats(s *otelstats.Stats) Option {
+	return optionFunc(func(conf config) config {
+		conf.stats = s
+		return conf
+	})
+}
@@ -0,0 +1,193 @@
+package autologs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/noop"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/go-faster/sdk/internal/otlpjson"
+	"github.com/go-faster/sdk/otelconfig"
+	"github.com/go-faster/sdk/zctx"
+)
+
+// newFromConfig creates LoggerProvider from declarative configuration.
+func newFromConfig(ctx context.Context, cfg config, c *otelconfig.Config, level zapcore.LevelEnabler) (
+	logProvider log.LoggerProvider,
+	logShutdown ShutdownFunc,
+	rerr error,
+) {
+	lp := c.LoggerProvider
+	if c.Disabled || lp == nil {
+		zctx.From(ctx).Debug("Logger provider is not configured, using no-op")
+		return noop.NewLoggerProvider(), nop, nil
+	}
+	res, err := c.MergeResource(ctx, cfg.res)
+	if err != nil {
+		return nil, nil, err
+	}
+	var logOptions []sdklog.LoggerProviderOption
+	if res != nil {
+		logOptions = append(logOptions, sdklog.WithResource(res))
+	}
+
+	var processors []sdklog.Processor
+	defer func() {
+		if rerr == nil {
+			return
+		}
+		for _, p := range processors {
+			_ = p.Shutdown(ctx)
+		}
+	}()
+	for i, p := range lp.Processors {
+		lrp, err := newLogProcessor(ctx, cfg, c, fmt.Sprintf("logger_provider.processors[%d]", i), p)
+		if err != nil {
+			return nil, nil, err
+		}
+		processors = append(processors, lrp)
+		logOptions = append(logOptions, sdklog.WithProcessor(&levelFilterProcessor{
+			next:  lrp,
+			level: level,
+		}))
+	}
+	provider := sdklog.NewLoggerProvider(logOptions...)
+	return provider, provider.Shutdown, nil
+}
+
+func newLogProcessor(ctx context.Context, cfg config, c *otelconfig.Config, path string, p otelconfig.Processor) (sdklog.Processor, error) {
+	if b := p.Batch; b != nil {
+		exp, err := newLogExporter(ctx, cfg, c, path+".batch.exporter", b.Exporter)
+		if err != nil {
+			return nil, err
+		}
+		var opts []sdklog.BatchProcessorOption
+		if v := b.ScheduleDelay; v != nil {
+			opts = append(opts, sdklog.WithExportInterval(v.Duration()))
+		}
+		if v := b.ExportTimeout; v != nil {
+			opts = append(opts, sdklog.WithExportTimeout(v.Duration()))
+		}
+		var queueSize int
+		if v := b.MaxQueueSize; v != nil {
+			queueSize = *v
+			opts = append(opts, sdklog.WithMaxQueueSize(*v))
+		}
+		if v := b.MaxExportBatchSize; v != nil {
+			opts = append(opts, sdklog.WithExportMaxBatchSize(*v))
+		}
+		return cfg.stats.BatchLogProcessor(b.Exporter.Name(), exp, queueSize, opts...), nil
+	}
+	exp, err := newLogExporter(ctx, cfg, c, path+".simple.exporter", p.Simple.Exporter)
+	if err != nil {
+		return nil, err
+	}
+	return sdklog.NewSimpleProcessor(cfg.stats.LogExporter(p.Simple.Exporter.Name(), exp)), nil
+}
+
+func newLogExporter(ctx context.Context, cfg config, c *otelconfig.Config, path string, e otelconfig.Exporter) (sdklog.Exporter, error) {
+	var (
+		lg   = zctx.From(ctx)
+		name = e.Name()
+	)
+	path += "." + name
+	if o, proto, ok := e.OTLPConfig(); ok {
+		lg.Debug("Using OTLP logs exporter", zap.String("protocol", proto))
+		switch proto {
+		case otelconfig.ProtocolHTTPProtobuf, otelconfig.ProtocolHTTPJSON:
+			var opts []otlploghttp.Option
+			if v := o.Endpoint; strings.Contains(v, "://") {
+				opts = append(opts, otlploghttp.WithEndpointURL(v))
+			} else if v != "" {
+				opts = append(opts, otlploghttp.WithEndpoint(v))
+			}
+			if o.Insecure {
+				opts = append(opts, otlploghttp.WithInsecure())
+			}
+			if h := o.HeaderMap(); len(h) > 0 {
+				opts = append(opts, otlploghttp.WithHeaders(h))
+			}
+			if o.Compression == "gzip" {
+				opts = append(opts, otlploghttp.WithCompression(otlploghttp.GzipCompression))
+			}
+			if v := o.Timeout; v != nil {
+				opts = append(opts, otlploghttp.WithTimeout(v.Duration()))
+			}
+			if proto == otelconfig.ProtocolHTTPJSON {
+				client, err := otlpjson.ConfigFromEnv(otlpjson.Logs)
+				if err != nil {
+					return nil, c.Wrap(path, errors.Wrap(err, "configure OTLP JSON client"))
+				}
+				if v := o.Timeout; v != nil {
+					client.Timeout = v.Duration()
+				}
+				opts = append(opts, otlploghttp.WithHTTPClient(otlpjson.NewClient(otlpjson.Logs, client)))
+			}
+			exp, err := otlploghttp.New(ctx, opts...)
+			if err != nil {
+				return nil, c.Wrap(path, errors.Wrap(err, "create OTLP HTTP logs exporter"))
+			}
+			return exp, nil
+		case otelconfig.ProtocolGRPC:
+			var opts []otlploggrpc.Option
+			if v := o.Endpoint; strings.Contains(v, "://") {
+				opts = append(opts, otlploggrpc.WithEndpointURL(v))
+			} else if v != "" {
+				opts = append(opts, otlploggrpc.WithEndpoint(v))
+			}
+			if o.Insecure {
+				opts = append(opts, otlploggrpc.WithInsecure())
+			}
+			if h := o.HeaderMap(); len(h) > 0 {
+				opts = append(opts, otlploggrpc.WithHeaders(h))
+			}
+			if o.Compression == "gzip" {
+				opts = append(opts, otlploggrpc.WithCompressor("gzip"))
+			}
+			if v := o.Timeout; v != nil {
+				opts = append(opts, otlploggrpc.WithTimeout(v.Duration()))
+			}
+			exp, err := otlploggrpc.New(ctx, opts...)
+			if err != nil {
+				return nil, c.Wrap(path, errors.Wrap(err, "create OTLP gRPC logs exporter"))
+			}
+			return exp, nil
+		default:
+			return nil, c.Errorf(path, "unsupported logs otlp protocol %q", proto)
+		}
+	}
+	if e.Console != nil {
+		lg.Debug("Using console logs exporter")
+		writer := cfg.writer
+		if writer == nil {
+			writer = os.Stdout
+		}
+		exp, err := stdoutlog.New(stdoutlog.WithWriter(writer))
+		if err != nil {
+			return nil, c.Wrap(path, errors.Wrap(err, "create console logs exporter"))
+		}
+		return exp, nil
+	}
+	if lookup := cfg.lookup; lookup != nil {
+		lg.Debug("Looking for logs exporter", zap.String("exporter", name))
+		exp, ok, err := lookup(ctx, name)
+		if err != nil {
+			return nil, c.Wrap(path, errors.Wrapf(err, "create %q", name))
+		}
+		if ok {
+			lg.Debug("Using user-defined log exporter", zap.String("exporter", name))
+			return exp, nil
+		}
+	}
+	return nil, c.Errorf(path, "unsupported logs exporter %q", name)
+}
@@ -7,20 +7,21 @@ import (
 	"encoding/json"
 	"io"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/go-faster/errors"
-	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/collectors"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
-	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/metric/noop"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
 	"go.uber.org/zap"
 
+	"github.com/go-faster/sdk/internal/otlpjson"
+	"github.com/go-faster/sdk/otelconfig"
 	"github.com/go-faster/sdk/zctx"
 )
 
@@ -31,6 +32,7 @@ const (
 
 	protoHTTP         = "http"
 	protoHTTPProtobuf = "http/protobuf"
+	protoHTTPJSON     = "http/json"
 	protoGRPC         = "grpc"
 	defaultProto      = protoGRPC
 )
@@ -60,6 +62,25 @@ func getEnvOr(name, def string) string {
 
 func noopHandler(_ context.Context) error { return nil }
 
+const (
+	exemplarAlwaysOn   = "always_on"
+	exemplarAlwaysOff  = "always_off"
+	exemplarTraceBased = "trace_based"
+)
+
+func exemplarFilterByName(name string) (exemplar.Filter, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case exemplarAlwaysOn:
+		return exemplar.AlwaysOnFilter, nil
+	case exemplarAlwaysOff:
+		return exemplar.AlwaysOffFilter, nil
+	case exemplarTraceBased:
+		return exemplar.TraceBasedFilter, nil
+	default:
+		return nil, errors.Errorf("unsupported OTEL_METRICS_EXEMPLAR_FILTER %q", name)
+	}
+}
+
 // ShutdownFunc is a function that shuts down the MeterProvider.
 type ShutdownFunc func(ctx context.Context) error
 
@@ -71,45 +92,122 @@ func NewMeterProvider(ctx context.Context, options ...Option) (
 ) {
 	cfg := newConfig(options)
 	lg := zctx.From(ctx)
+	cfg.overflows = newOverflowReporter(lg)
+	if cfg.file == nil {
+		if cfg.file, err = otelconfig.FromEnv(); err != nil {
+			return nil, nil, errors.Wrap(err, "load config")
+		}
+	}
+	if cfg.file != nil {
+		lg.Debug("Using declarative configuration")
+		return newFromConfig(ctx, cfg, cfg.file)
+	}
 	var metricOptions []sdkmetric.Option
 	if cfg.res != nil {
 		metricOptions = append(metricOptions, sdkmetric.WithResource(cfg.res))
 	}
+	filter := cfg.exemplarFilter
+	if v := os.Getenv("OTEL_METRICS_EXEMPLAR_FILTER"); filter == nil && v != "" {
+		if filter, err = exemplarFilterByName(v); err != nil {
+			return nil, nil, err
+		}
+	}
+	if filter != nil {
+		metricOptions = append(metricOptions, sdkmetric.WithExemplarFilter(filter))
+	}
+	views, err := viewsFromEnv()
+	if err != nil {
+		return nil, nil, err
+	}
+	views = append(views, cfg.views...)
+	for _, v := range views {
+		metricOptions = append(metricOptions, sdkmetric.WithView(v))
+	}
+	limits, err := cardinalityFromEnv(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Lower limits are enforced by limitCardinality.
+	metricOptions = append(metricOptions, sdkmetric.WithCardinalityLimit(limits.sdk()))
+	env, err := parseReaderEnv()
+	if err != nil {
+		return nil, nil, err
+	}
 
-	ret := func(r sdkmetric.Reader) (metric.MeterProvider, func(ctx context.Context) error, error) {
+	// Metrics exporters.
+	exporters := exporterNames(lg, getEnvOr("OTEL_METRICS_EXPORTER", expOTLP))
+	if len(exporters) == 0 {
+		lg.Debug("Using no-op metrics exporter")
+		return noop.NewMeterProvider(), noopHandler, nil
+	}
+	var (
+		created     []sdkmetric.Reader
+		temporality []sdkmetric.TemporalitySelector
+	)
+	for _, exporter := range exporters {
+		r, err := newEnvReader(ctx, cfg, env, exporter)
+		if err != nil {
+			// Releasing already created readers.
+			for _, r := range created {
+				_ = r.Shutdown(ctx)
+			}
+			return nil, nil, err
+		}
+		created = append(created, r)
 		metricOptions = append(metricOptions, sdkmetric.WithReader(r))
-		provider := sdkmetric.NewMeterProvider(metricOptions...)
-		return provider, provider.Shutdown, nil
+
+		switch exporter {
+		case expOTLP, writerStdout, writerStderr:
+			temporality = append(temporality, env.temporality)
+		default:
+			// Prometheus is cumulative, user-defined is unknown.
+			temporality = append(temporality, nil)
+		}
+	}
+	provider := sdkmetric.NewMeterProvider(metricOptions...)
+	mp, err := wrapProvider(cfg, provider, limits, views, deltaKinds(temporality))
+	if err != nil {
+		_ = provider.Shutdown(ctx)
+		return nil, nil, err
+	}
+	return mp, provider.Shutdown, nil
+}
+
+// exporterNames returns exporter names from comma-separated list.
+//
+// Empty entries and duplicates are skipped. As per specification, "none"
+// is ignored if other exporters are set. Empty result means no exporters.
+func exporterNames(lg *zap.Logger, v string) []string {
+	var (
+		names []string
+		none  bool
+	)
+	for name := range strings.SplitSeq(v, ",") {
+		switch name = strings.TrimSpace(name); {
+		case name == "" || slices.Contains(names, name):
+		case name == expNone:
+			none = true
+		default:
+			names = append(names, name)
+		}
+	}
+	if none && len(names) > 0 {
+		lg.Warn("Ignoring metrics exporter \"none\" mixed with other exporters", zap.Strings("exporters", names))
 	}
+	return names
+}
 
-	// Metrics exporter.
-	exporter := strings.TrimSpace(getEnvOr("OTEL_METRICS_EXPORTER", expOTLP))
+// newEnvReader creates metric reader for exporter name.
+func newEnvReader(ctx context.Context, cfg config, env readerEnv, exporter string) (sdkmetric.Reader, error) {
+	lg := zctx.From(ctx)
 	switch exporter {
 	case expPrometheus:
 		lg.Debug("Using Prometheus metrics exporter")
-		reg := cfg.prom
-		if reg == nil {
-			reg = prometheus.NewPedanticRegistry()
-		}
-		if cfg.promCallback != nil {
-			switch v := reg.(type) {
-			case *prometheus.Registry:
-				cfg.promCallback(v)
-			}
-		}
-		exp, err := otelprometheus.New(
-			otelprometheus.WithRegisterer(reg),
-		)
+		p, err := prometheusFromEnv()
 		if err != nil {
-			return nil, nil, errors.Wrap(err, "create Prometheus exporter")
-		}
-		// Register legacy prometheus-only runtime metrics for backward compatibility.
-		reg.MustRegister(
-			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
-			collectors.NewGoCollector(),
-			collectors.NewBuildInfoCollector(),
-		)
-		return ret(exp)
+			return nil, err
+		}
+		return newPrometheusReader(cfg, p)
 	case expOTLP:
 		proto := os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
 		if proto == "" {
@@ -120,20 +218,41 @@ func NewMeterProvider(ctx context.Context, options ...Option) (
 		}
 		lg.Debug("Using OTLP metrics exporter", zap.String("protocol", proto))
 		switch proto {
-		case protoHTTP, protoHTTPProtobuf:
-			exp, err := otlpmetrichttp.New(ctx)
+		case protoHTTP, protoHTTPProtobuf, protoHTTPJSON:
+			var opts []otlpmetrichttp.Option
+			if proto == protoHTTPJSON {
+				client, err := otlpjson.ConfigFromEnv(otlpjson.Metrics)
+				if err != nil {
+					return nil, errors.Wrap(err, "configure OTLP JSON client")
+				}
+				opts = append(opts, otlpmetrichttp.WithHTTPClient(otlpjson.NewClient(otlpjson.Metrics, client)))
+			}
+			if env.temporality != nil {
+				opts = append(opts, otlpmetrichttp.WithTemporalitySelector(env.temporality))
+			}
+			if env.aggregation != nil {
+				opts = append(opts, otlpmetrichttp.WithAggregationSelector(env.aggregation))
+			}
+			exp, err := otlpmetrichttp.New(ctx, opts...)
 			if err != nil {
-				return nil, nil, errors.Wrap(err, "create OTLP HTTP metric exporter")
+				return nil, errors.Wrap(err, "create OTLP HTTP metric exporter")
 			}
-			return ret(sdkmetric.NewPeriodicReader(exp))
+			return sdkmetric.NewPeriodicReader(cfg.metricExporter(exporter, exp), env.periodic...), nil
 		case protoGRPC:
-			exp, err := otlpmetricgrpc.New(ctx)
+			var opts []otlpmetricgrpc.Option
+			if env.temporality != nil {
+				opts = append(opts, otlpmetricgrpc.WithTemporalitySelector(env.temporality))
+			}
+			if env.aggregation != nil {
+				opts = append(opts, otlpmetricgrpc.WithAggregationSelector(env.aggregation))
+			}
+			exp, err := otlpmetricgrpc.New(ctx, opts...)
 			if err != nil {
-				return nil, nil, errors.Wrap(err, "create OTLP gRPC metric exporter")
+				return nil, errors.Wrap(err, "create OTLP gRPC metric exporter")
 			}
-			return ret(sdkmetric.NewPeriodicReader(exp))
+			return sdkmetric.NewPeriodicReader(cfg.metricExporter(exporter, exp), env.periodic...), nil
 		default:
-			return nil, nil, errors.Errorf("unsupported metric OTLP protocol %q", proto)
+			return nil, errors.Errorf("unsupported metric OTLP protocol %q", proto)
 		}
 	case writerStdout, writerStderr:
 		lg.Debug("Using stdout metrics exporter", zap.String("writer", exporter))
@@ -141,15 +260,20 @@ func NewMeterProvider(ctx context.Context, options ...Option) (
 		if writer == nil {
 			writer = writerByName(exporter)
 		}
-		enc := json.NewEncoder(writer)
-		exp, err := stdoutmetric.New(stdoutmetric.WithEncoder(enc))
+		opts := []stdoutmetric.Option{
+			stdoutmetric.WithEncoder(json.NewEncoder(writer)),
+		}
+		if env.temporality != nil {
+			opts = append(opts, stdoutmetric.WithTemporalitySelector(env.temporality))
+		}
+		if env.aggregation != nil {
+			opts = append(opts, stdoutmetric.WithAggregationSelector(env.aggregation))
+		}
+		exp, err := stdoutmetric.New(opts...)
 		if err != nil {
-			return nil, nil, errors.Wrapf(err, "create %q metric exporter", exporter)
+			return nil, errors.Wrapf(err, "create %q metric exporter", exporter)
 		}
-		return ret(sdkmetric.NewPeriodicReader(exp))
-	case expNone:
-		lg.Debug("Using no-op metrics exporter")
-		return noop.NewMeterProvider(), noopHandler, nil
+		return sdkmetric.NewPeriodicReader(cfg.metricExporter(exporter, exp), env.periodic...), nil
 	default:
 		lookup := cfg.lookup
 		if lookup == nil {
@@ -158,14 +282,14 @@ func NewMeterProvider(ctx context.Context, options ...Option) (
 		lg.Debug("Looking for metrics exporter", zap.String("exporter", exporter))
 		exp, ok, err := lookup(ctx, exporter)
 		if err != nil {
-			return nil, nil, errors.Wrapf(err, "create %q", exporter)
+			return nil, errors.Wrapf(err, "create %q", exporter)
 		}
 		if !ok {
 			break
 		}
 
 		lg.Debug("Using user-defined metrics exporter", zap.String("exporter", exporter))
-		return ret(exp)
+		return exp, nil
 	}
-	return nil, nil, errors.Errorf("unsupported OTEL_METRICS_EXPORTER %q", exporter)
+	return nil, errors.Errorf("unsupported OTEL_METRICS_EXPORTER %q", exporter)
 }
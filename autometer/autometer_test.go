@@ -3,9 +3,19 @@ package autometer_test
 import (
 	"context"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	"go.opentelemetry.io/otel/sdk/resource"
 
 	"github.com/go-faster/sdk/autometer"
@@ -31,6 +41,42 @@ func TestNewMeterProvider(t *testing.T) {
 		require.Nil(t, meter)
 		require.Nil(t, stop)
 	})
+	t.Run("ExemplarFilter", func(t *testing.T) {
+		t.Setenv("OTEL_METRICS_EXPORTER", "none")
+		for _, v := range []string{"always_on", "always_off", "trace_based"} {
+			t.Setenv("OTEL_METRICS_EXEMPLAR_FILTER", v)
+			_, _, err := autometer.NewMeterProvider(ctx, autometer.WithResource(res))
+			require.NoError(t, err)
+		}
+		t.Setenv("OTEL_METRICS_EXEMPLAR_FILTER", "sometimes")
+		_, _, err := autometer.NewMeterProvider(ctx, autometer.WithResource(res))
+		require.ErrorContains(t, err, `unsupported OTEL_METRICS_EXEMPLAR_FILTER "sometimes"`)
+	})
+	t.Run("ReaderEnv", func(t *testing.T) {
+		t.Setenv("OTEL_METRICS_EXPORTER", "stdout")
+		for _, tt := range []struct {
+			name, value, errMsg string
+		}{
+			{"OTEL_METRIC_EXPORT_INTERVAL", "10s", "parse OTEL_METRIC_EXPORT_INTERVAL"},
+			{"OTEL_METRIC_EXPORT_TIMEOUT", "0", "invalid OTEL_METRIC_EXPORT_TIMEOUT"},
+			{"OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE", "gauge", "unsupported OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE"},
+			{"OTEL_EXPORTER_OTLP_METRICS_DEFAULT_HISTOGRAM_AGGREGATION", "summary", "unsupported OTEL_EXPORTER_OTLP_METRICS_DEFAULT_HISTOGRAM_AGGREGATION"},
+			{"OTEL_METRICS_VIEWS_FILE", "/nonexistent/views.yaml", "load OTEL_METRICS_VIEWS_FILE"},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Setenv(tt.name, tt.value)
+				_, _, err := autometer.NewMeterProvider(ctx, autometer.WithWriter(io.Discard))
+				require.ErrorContains(t, err, tt.errMsg)
+			})
+		}
+		t.Setenv("OTEL_METRIC_EXPORT_INTERVAL", "1000")
+		t.Setenv("OTEL_METRIC_EXPORT_TIMEOUT", "500")
+		t.Setenv("OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE", "LowMemory")
+		t.Setenv("OTEL_EXPORTER_OTLP_METRICS_DEFAULT_HISTOGRAM_AGGREGATION", "base2_exponential_bucket_histogram")
+		_, stop, err := autometer.NewMeterProvider(ctx, autometer.WithWriter(io.Discard))
+		require.NoError(t, err)
+		require.NoError(t, stop(ctx))
+	})
 	t.Run("All", func(t *testing.T) {
 		for _, exp := range []string{
 			"none",
@@ -38,6 +84,8 @@ func TestNewMeterProvider(t *testing.T) {
 			"stderr",
 			// "otlp", // TODO: add non-blocking dial
 			"prometheus",
+			"prometheus,stdout",
+			"none,stderr",
 		} {
 			t.Run(exp, func(t *testing.T) {
 				t.Setenv("OTEL_METRICS_EXPORTER", exp)
@@ -52,3 +100,112 @@ func TestNewMeterProvider(t *testing.T) {
 		}
 	})
 }
+
+func TestNewMeterProviderMultipleReaders(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("OTEL_METRICS_EXPORTER", "first,second")
+	readers := map[string]*sdkmetric.ManualReader{
+		"first":  sdkmetric.NewManualReader(),
+		"second": sdkmetric.NewManualReader(),
+	}
+	provider, stop, err := autometer.NewMeterProvider(ctx,
+		autometer.WithLookupExporter(func(ctx context.Context, name string) (sdkmetric.Reader, bool, error) {
+			r, ok := readers[name]
+			return r, ok, nil
+		}),
+	)
+	require.NoError(t, err)
+
+	counter, err := provider.Meter("test").Int64Counter("requests")
+	require.NoError(t, err)
+	counter.Add(ctx, 1)
+	for name, r := range readers {
+		var rm metricdata.ResourceMetrics
+		require.NoError(t, r.Collect(ctx, &rm), name)
+		require.Len(t, rm.ScopeMetrics, 1, name)
+	}
+	require.NoError(t, stop(ctx))
+}
+
+func TestNewMeterProviderHTTPJSON(t *testing.T) {
+	ctx := context.Background()
+	received := make(chan pmetric.Metrics, 1)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/metrics", r.URL.Path)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		req := pmetricotlp.NewExportRequest()
+		require.NoError(t, req.UnmarshalJSON(data))
+		received <- req.Metrics()
+	}))
+	t.Cleanup(s.Close)
+	t.Setenv("OTEL_METRICS_EXPORTER", "otlp")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "http/json")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", s.URL)
+
+	provider, stop, err := autometer.NewMeterProvider(ctx)
+	require.NoError(t, err)
+	counter, err := provider.Meter("test").Int64Counter("requests")
+	require.NoError(t, err)
+	counter.Add(ctx, 1)
+	require.NoError(t, stop(ctx))
+
+	md := <-received
+	require.Equal(t, "requests", md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Name())
+}
+
+func TestNewMeterProviderViews(t *testing.T) {
+	ctx := context.Background()
+	name := filepath.Join(t.TempDir(), "views.yaml")
+	require.NoError(t, os.WriteFile(name, []byte(`views:
+  - selector:
+      instrument_name: requests
+    stream:
+      name: http.requests
+      attribute_keys:
+        excluded: [path]
+`), 0o600))
+	t.Setenv("OTEL_METRICS_EXPORTER", "manual")
+	t.Setenv("OTEL_METRICS_VIEWS_FILE", name)
+
+	reader := sdkmetric.NewManualReader()
+	provider, stop, err := autometer.NewMeterProvider(ctx,
+		autometer.WithLookupExporter(func(ctx context.Context, name string) (sdkmetric.Reader, bool, error) {
+			return reader, true, nil
+		}),
+		autometer.WithViews(sdkmetric.NewView(
+			sdkmetric.Instrument{Name: "latency"},
+			sdkmetric.Stream{Aggregation: sdkmetric.AggregationExplicitBucketHistogram{Boundaries: []float64{1, 10}}},
+		)),
+	)
+	require.NoError(t, err)
+
+	meter := provider.Meter("test")
+	counter, err := meter.Int64Counter("requests")
+	require.NoError(t, err)
+	counter.Add(ctx, 1, metric.WithAttributes(attribute.String("path", "/"), attribute.String("method", "GET")))
+	histogram, err := meter.Float64Histogram("latency")
+	require.NoError(t, err)
+	histogram.Record(ctx, 5)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	metrics := map[string]metricdata.Metrics{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m
+	}
+
+	sum, ok := metrics["http.requests"].Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 1)
+	_, hasPath := sum.DataPoints[0].Attributes.Value("path")
+	require.False(t, hasPath)
+
+	hist, ok := metrics["latency"].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Equal(t, []float64{1, 10}, hist.DataPoints[0].Bounds)
+
+	require.NoError(t, stop(ctx))
+}
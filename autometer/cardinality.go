@@ -0,0 +1,408 @@
+package autometer
+
+import (
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// Environment variables of cardinality limits.
+const (
+	// EnvCardinalityLimit is default limit of attribute sets per instrument.
+	EnvCardinalityLimit = "OTEL_METRICS_CARDINALITY_LIMIT"
+	// EnvCardinalityLimits is comma-separated list of per-instrument
+	// limits, like "http.server.request.duration=5000,db.client.operations=100".
+	EnvCardinalityLimits = "OTEL_METRICS_CARDINALITY_LIMITS"
+)
+
+const (
+	instrumentationName = "github.com/go-faster/sdk/autometer"
+
+	// defaultCardinalityLimit is same as SDK default.
+	defaultCardinalityLimit = 2000
+)
+
+// overflowKey is attribute of overflow series as defined by specification.
+const overflowKey = attribute.Key("otel.metric.overflow")
+
+// overflowOption records measurement to overflow series.
+var overflowOption = metric.WithAttributeSet(attribute.NewSet(overflowKey.Bool(true)))
+
+// cardinalityLimits is limits of distinct attribute sets per instrument.
+type cardinalityLimits struct {
+	limit     int            // default, zero or negative means no limit
+	overrides map[string]int // by lowercase instrument name
+}
+
+func (c cardinalityLimits) get(name string) int {
+	if v, ok := c.overrides[strings.ToLower(name)]; ok {
+		return v
+	}
+	return c.limit
+}
+
+// sdk returns limit enforced by SDK, which is the highest of limits or
+// zero if any of them is unlimited.
+func (c cardinalityLimits) sdk() int {
+	if c.limit <= 0 {
+		return 0
+	}
+	n := c.limit
+	for _, v := range c.overrides {
+		if v <= 0 {
+			return 0
+		}
+		n = max(n, v)
+	}
+	return n
+}
+
+// wrapped reports whether limit is lower than SDK one and should be
+// enforced by [limitCardinality].
+func (c cardinalityLimits) wrapped(limit int) bool {
+	sdk := c.sdk()
+	return limit > 0 && (sdk <= 0 || limit < sdk)
+}
+
+// enabled reports whether any instrument is limited by [limitCardinality].
+func (c cardinalityLimits) enabled() bool {
+	if c.wrapped(c.limit) {
+		return true
+	}
+	for _, v := range c.overrides {
+		if c.wrapped(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// cardinalityFromConfig returns limits set by options.
+func cardinalityFromConfig(cfg config) cardinalityLimits {
+	c := cardinalityLimits{
+		limit:     defaultCardinalityLimit,
+		overrides: map[string]int{},
+	}
+	if cfg.cardinalityLimit != nil {
+		c.limit = *cfg.cardinalityLimit
+	}
+	for name, v := range cfg.cardinalityLimits {
+		c.overrides[strings.ToLower(name)] = v
+	}
+	return c
+}
+
+// cardinalityFromEnv returns limits set by environment variables, options
+// take precedence.
+func cardinalityFromEnv(cfg config) (cardinalityLimits, error) {
+	c := cardinalityFromConfig(cfg)
+	if v := os.Getenv(EnvCardinalityLimit); v != "" && cfg.cardinalityLimit == nil {
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return c, errors.Wrapf(err, "parse %s", EnvCardinalityLimit)
+		}
+		c.limit = n
+	}
+	for entry := range strings.SplitSeq(os.Getenv(EnvCardinalityLimits), ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		name, v, ok := strings.Cut(entry, "=")
+		if !ok {
+			return c, errors.Errorf("invalid %s entry %q: expected name=limit", EnvCardinalityLimits, entry)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return c, errors.Wrapf(err, "parse %s entry %q", EnvCardinalityLimits, entry)
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := c.overrides[name]; !ok {
+			c.overrides[name] = n
+		}
+	}
+	return c, nil
+}
+
+// limitCardinality returns provider that limits number of distinct
+// attribute sets of instruments with limit lower than [cardinalityLimits.sdk],
+// which should be set as SDK limit. Other instruments are not wrapped.
+//
+// Measurements with new attribute sets over limit are recorded to
+// overflow series, which is reported by [overflowReporter] as SDK one.
+// Attribute sets are counted as recorded by views, for synchronous
+// instruments since creation or, if delta reports true for instrument kind,
+// since previous collection. Asynchronous instruments are limited during
+// single callback.
+func limitCardinality(mp metric.MeterProvider, limits cardinalityLimits, views []sdkmetric.View, delta func(sdkmetric.InstrumentKind) bool) *limitedProvider {
+	if !limits.enabled() {
+		return nil
+	}
+	return &limitedProvider{
+		MeterProvider: mp,
+		limits:        limits,
+		views:         views,
+		delta:         delta,
+		meters:        map[meterKey]*limitedMeter{},
+	}
+}
+
+// wrapProvider limits cardinality of provider and reports its overflows.
+//
+// The delta reports whether all readers use delta temporality for
+// instrument kind, see [deltaKinds].
+func wrapProvider(cfg config, provider *sdkmetric.MeterProvider, limits cardinalityLimits, views []sdkmetric.View, delta func(sdkmetric.InstrumentKind) bool) (metric.MeterProvider, error) {
+	limited := limitCardinality(provider, limits, views, delta)
+	if err := cfg.overflows.init(provider, limited); err != nil {
+		return nil, errors.Wrap(err, "create overflows counter")
+	}
+	if limited == nil {
+		return provider, nil
+	}
+	return limited, nil
+}
+
+// deltaKinds returns function that reports whether all readers use delta
+// temporality for instrument kind, or nil if there are readers with
+// cumulative or unknown (nil) temporality.
+func deltaKinds(selectors []sdkmetric.TemporalitySelector) func(sdkmetric.InstrumentKind) bool {
+	if len(selectors) == 0 {
+		return nil
+	}
+	for _, s := range selectors {
+		if s == nil {
+			return nil
+		}
+	}
+	return func(kind sdkmetric.InstrumentKind) bool {
+		for _, s := range selectors {
+			if s(kind) != metricdata.DeltaTemporality {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+type meterKey struct {
+	name      string
+	version   string
+	schemaURL string
+	attrs     attribute.Distinct
+}
+
+type limitedProvider struct {
+	metric.MeterProvider
+
+	limits cardinalityLimits
+	views  []sdkmetric.View
+	delta  func(sdkmetric.InstrumentKind) bool // nil if all cumulative
+
+	mux      sync.Mutex
+	meters   map[meterKey]*limitedMeter
+	limiters []*limiter
+}
+
+// collected resets attribute sets of synchronous instruments with delta
+// temporality, so their limits apply to each collection as in SDK.
+func (p *limitedProvider) collected() {
+	if p == nil || p.delta == nil {
+		return
+	}
+	p.mux.Lock()
+	limiters := slices.Clone(p.limiters)
+	p.mux.Unlock()
+
+	for _, l := range limiters {
+		if p.delta(l.kind) {
+			l.reset()
+		}
+	}
+}
+
+// Meter implements [metric.MeterProvider].
+func (p *limitedProvider) Meter(name string, opts ...metric.MeterOption) metric.Meter {
+	cfg := metric.NewMeterConfig(opts...)
+	scope := instrumentation.Scope{
+		Name:       name,
+		Version:    cfg.InstrumentationVersion(),
+		SchemaURL:  cfg.SchemaURL(),
+		Attributes: cfg.InstrumentationAttributes(),
+	}
+	key := meterKey{
+		name:      name,
+		version:   scope.Version,
+		schemaURL: scope.SchemaURL,
+		attrs:     scope.Attributes.Equivalent(),
+	}
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if m, ok := p.meters[key]; ok {
+		return m
+	}
+	m := &limitedMeter{
+		Meter:    p.MeterProvider.Meter(name, opts...),
+		provider: p,
+		scope:    scope,
+		limiters: map[limiterKey]*limiter{},
+	}
+	p.meters[key] = m
+	return m
+}
+
+// attributeFilter returns filter of attributes that views record for
+// instrument, or false if instrument is dropped.
+func (p *limitedProvider) attributeFilter(inst sdkmetric.Instrument) (attribute.Filter, bool) {
+	var (
+		matched bool
+		filters []attribute.Filter
+	)
+	for _, view := range p.views {
+		s, ok := view(inst)
+		if !ok {
+			continue
+		}
+		matched = true
+		if _, drop := s.Aggregation.(sdkmetric.AggregationDrop); drop {
+			continue
+		}
+		if s.AttributeFilter == nil {
+			return nil, true
+		}
+		filters = append(filters, s.AttributeFilter)
+	}
+	switch {
+	case !matched:
+		return nil, true
+	case len(filters) == 0:
+		return nil, false
+	case len(filters) == 1:
+		return filters[0], true
+	}
+	// Instrument produces stream for each view, counting attributes of all.
+	return func(kv attribute.KeyValue) bool {
+		for _, f := range filters {
+			if f(kv) {
+				return true
+			}
+		}
+		return false
+	}, true
+}
+
+type limiterKey struct {
+	name string
+	kind sdkmetric.InstrumentKind
+}
+
+type limitedMeter struct {
+	metric.Meter
+
+	provider *limitedProvider
+	scope    instrumentation.Scope
+
+	mux      sync.Mutex
+	limiters map[limiterKey]*limiter
+}
+
+// limiter returns limiter of instrument or nil if instrument is not limited.
+func (m *limitedMeter) limiter(name string, kind sdkmetric.InstrumentKind, unit string) *limiter {
+	p := m.provider
+	limit := p.limits.get(name)
+	if !p.limits.wrapped(limit) {
+		return nil
+	}
+	filter, ok := p.attributeFilter(sdkmetric.Instrument{
+		Name:  name,
+		Kind:  kind,
+		Unit:  unit,
+		Scope: m.scope,
+	})
+	if !ok {
+		return nil
+	}
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	key := limiterKey{name: strings.ToLower(name), kind: kind}
+	if l, ok := m.limiters[key]; ok {
+		return l
+	}
+	l := &limiter{
+		kind:   kind,
+		limit:  limit,
+		filter: filter,
+		sets:   map[attribute.Distinct]struct{}{},
+	}
+	m.limiters[key] = l
+
+	p.mux.Lock()
+	p.limiters = append(p.limiters, l)
+	p.mux.Unlock()
+	return l
+}
+
+// limiter limits distinct attribute sets of single instrument.
+type limiter struct {
+	kind   sdkmetric.InstrumentKind
+	limit  int
+	filter attribute.Filter // nil if all attributes are recorded
+
+	mux  sync.RWMutex
+	sets map[attribute.Distinct]struct{} // of synchronous instrument
+}
+
+func (l *limiter) reset() {
+	l.mux.Lock()
+	clear(l.sets)
+	l.mux.Unlock()
+}
+
+func (l *limiter) key(set attribute.Set) attribute.Distinct {
+	if l.filter != nil {
+		set, _ = set.Filter(l.filter)
+	}
+	return set.Equivalent()
+}
+
+// add adds k to seen if it is within limit, which includes overflow series.
+func (l *limiter) add(seen map[attribute.Distinct]struct{}, k attribute.Distinct) bool {
+	if _, ok := seen[k]; ok {
+		return true
+	}
+	if len(seen) >= l.limit-1 {
+		return false
+	}
+	seen[k] = struct{}{}
+	return true
+}
+
+// allow reports whether measurement of synchronous instrument is within limit.
+func (l *limiter) allow(set attribute.Set) bool {
+	k := l.key(set)
+	l.mux.RLock()
+	_, ok := l.sets[k]
+	l.mux.RUnlock()
+	if ok {
+		return true
+	}
+	l.mux.Lock()
+	ok = l.add(l.sets, k)
+	l.mux.Unlock()
+	return ok
+}
+
+// allowObserved is [limiter.allow] for asynchronous instrument, with
+// attribute sets seen during current callback.
+func (l *limiter) allowObserved(seen map[attribute.Distinct]struct{}, set attribute.Set) bool {
+	return l.add(seen, l.key(set))
+}
@@ -0,0 +1,465 @@
+package autometer
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// Synchronous instruments are created with original options and replace
+// attributes of measurements over limit.
+
+func (m *limitedMeter) Int64Counter(name string, options ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	i, err := m.Meter.Int64Counter(name, options...)
+	if i == nil {
+		return i, err
+	}
+	l := m.limiter(name, sdkmetric.InstrumentKindCounter, metric.NewInt64CounterConfig(options...).Unit())
+	if l == nil {
+		return i, err
+	}
+	return &int64Counter{Int64Counter: i, l: l}, err
+}
+
+type int64Counter struct {
+	metric.Int64Counter
+	l *limiter
+}
+
+func (i *int64Counter) Add(ctx context.Context, incr int64, options ...metric.AddOption) {
+	if !i.l.allow(metric.NewAddConfig(options).Attributes()) {
+		options = []metric.AddOption{overflowOption}
+	}
+	i.Int64Counter.Add(ctx, incr, options...)
+}
+
+func (m *limitedMeter) Int64UpDownCounter(name string, options ...metric.Int64UpDownCounterOption) (metric.Int64UpDownCounter, error) {
+	i, err := m.Meter.Int64UpDownCounter(name, options...)
+	if i == nil {
+		return i, err
+	}
+	l := m.limiter(name, sdkmetric.InstrumentKindUpDownCounter, metric.NewInt64UpDownCounterConfig(options...).Unit())
+	if l == nil {
+		return i, err
+	}
+	return &int64UpDownCounter{Int64UpDownCounter: i, l: l}, err
+}
+
+type int64UpDownCounter struct {
+	metric.Int64UpDownCounter
+	l *limiter
+}
+
+func (i *int64UpDownCounter) Add(ctx context.Context, incr int64, options ...metric.AddOption) {
+	if !i.l.allow(metric.NewAddConfig(options).Attributes()) {
+		options = []metric.AddOption{overflowOption}
+	}
+	i.Int64UpDownCounter.Add(ctx, incr, options...)
+}
+
+func (m *limitedMeter) Int64Histogram(name string, options ...metric.Int64HistogramOption) (metric.Int64Histogram, error) {
+	i, err := m.Meter.Int64Histogram(name, options...)
+	if i == nil {
+		return i, err
+	}
+	l := m.limiter(name, sdkmetric.InstrumentKindHistogram, metric.NewInt64HistogramConfig(options...).Unit())
+	if l == nil {
+		return i, err
+	}
+	return &int64Histogram{Int64Histogram: i, l: l}, err
+}
+
+type int64Histogram struct {
+	metric.Int64Histogram
+	l *limiter
+}
+
+func (i *int64Histogram) Record(ctx context.Context, value int64, options ...metric.RecordOption) {
+	if !i.l.allow(metric.NewRecordConfig(options).Attributes()) {
+		options = []metric.RecordOption{overflowOption}
+	}
+	i.Int64Histogram.Record(ctx, value, options...)
+}
+
+func (m *limitedMeter) Int64Gauge(name string, options ...metric.Int64GaugeOption) (metric.Int64Gauge, error) {
+	i, err := m.Meter.Int64Gauge(name, options...)
+	if i == nil {
+		return i, err
+	}
+	l := m.limiter(name, sdkmetric.InstrumentKindGauge, metric.NewInt64GaugeConfig(options...).Unit())
+	if l == nil {
+		return i, err
+	}
+	return &int64Gauge{Int64Gauge: i, l: l}, err
+}
+
+type int64Gauge struct {
+	metric.Int64Gauge
+	l *limiter
+}
+
+func (i *int64Gauge) Record(ctx context.Context, value int64, options ...metric.RecordOption) {
+	if !i.l.allow(metric.NewRecordConfig(options).Attributes()) {
+		options = []metric.RecordOption{overflowOption}
+	}
+	i.Int64Gauge.Record(ctx, value, options...)
+}
+
+func (m *limitedMeter) Float64Counter(name string, options ...metric.Float64CounterOption) (metric.Float64Counter, error) {
+	i, err := m.Meter.Float64Counter(name, options...)
+	if i == nil {
+		return i, err
+	}
+	l := m.limiter(name, sdkmetric.InstrumentKindCounter, metric.NewFloat64CounterConfig(options...).Unit())
+	if l == nil {
+		return i, err
+	}
+	return &float64Counter{Float64Counter: i, l: l}, err
+}
+
+type float64Counter struct {
+	metric.Float64Counter
+	l *limiter
+}
+
+func (i *float64Counter) Add(ctx context.Context, incr float64, options ...metric.AddOption) {
+	if !i.l.allow(metric.NewAddConfig(options).Attributes()) {
+		options = []metric.AddOption{overflowOption}
+	}
+	i.Float64Counter.Add(ctx, incr, options...)
+}
+
+func (m *limitedMeter) Float64UpDownCounter(name string, options ...metric.Float64UpDownCounterOption) (metric.Float64UpDownCounter, error) {
+	i, err := m.Meter.Float64UpDownCounter(name, options...)
+	if i == nil {
+		return i, err
+	}
+	l := m.limiter(name, sdkmetric.InstrumentKindUpDownCounter, metric.NewFloat64UpDownCounterConfig(options...).Unit())
+	if l == nil {
+		return i, err
+	}
+	return &float64UpDownCounter{Float64UpDownCounter: i, l: l}, err
+}
+
+type float64UpDownCounter struct {
+	metric.Float64UpDownCounter
+	l *limiter
+}
+
+func (i *float64UpDownCounter) Add(ctx context.Context, incr float64, options ...metric.AddOption) {
+	if !i.l.allow(metric.NewAddConfig(options).Attributes()) {
+		options = []metric.AddOption{overflowOption}
+	}
+	i.Float64UpDownCounter.Add(ctx, incr, options...)
+}
+
+func (m *limitedMeter) Float64Histogram(name string, options ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	i, err := m.Meter.Float64Histogram(name, options...)
+	if i == nil {
+		return i, err
+	}
+	l := m.limiter(name, sdkmetric.InstrumentKindHistogram, metric.NewFloat64HistogramConfig(options...).Unit())
+	if l == nil {
+		return i, err
+	}
+	return &float64Histogram{Float64Histogram: i, l: l}, err
+}
+
+type float64Histogram struct {
+	metric.Float64Histogram
+	l *limiter
+}
+
+func (i *float64Histogram) Record(ctx context.Context, value float64, options ...metric.RecordOption) {
+	if !i.l.allow(metric.NewRecordConfig(options).Attributes()) {
+		options = []metric.RecordOption{overflowOption}
+	}
+	i.Float64Histogram.Record(ctx, value, options...)
+}
+
+func (m *limitedMeter) Float64Gauge(name string, options ...metric.Float64GaugeOption) (metric.Float64Gauge, error) {
+	i, err := m.Meter.Float64Gauge(name, options...)
+	if i == nil {
+		return i, err
+	}
+	l := m.limiter(name, sdkmetric.InstrumentKindGauge, metric.NewFloat64GaugeConfig(options...).Unit())
+	if l == nil {
+		return i, err
+	}
+	return &float64Gauge{Float64Gauge: i, l: l}, err
+}
+
+type float64Gauge struct {
+	metric.Float64Gauge
+	l *limiter
+}
+
+func (i *float64Gauge) Record(ctx context.Context, value float64, options ...metric.RecordOption) {
+	if !i.l.allow(metric.NewRecordConfig(options).Attributes()) {
+		options = []metric.RecordOption{overflowOption}
+	}
+	i.Float64Gauge.Record(ctx, value, options...)
+}
+
+// Asynchronous instruments are created with callbacks that replace
+// attributes of observations over limit.
+
+// limitedObservable is implemented by observable instruments with limiter.
+type limitedObservable interface {
+	unwrap() (metric.Observable, *limiter)
+}
+
+func (m *limitedMeter) Int64ObservableCounter(name string, options ...metric.Int64ObservableCounterOption) (metric.Int64ObservableCounter, error) {
+	cfg := metric.NewInt64ObservableCounterConfig(options...)
+	l := m.limiter(name, sdkmetric.InstrumentKindObservableCounter, cfg.Unit())
+	if l == nil {
+		return m.Meter.Int64ObservableCounter(name, options...)
+	}
+	i, err := m.Meter.Int64ObservableCounter(name, int64ObservableOptions[metric.Int64ObservableCounterOption](l, cfg.Description(), cfg.Unit(), cfg.Callbacks())...)
+	if i == nil {
+		return i, err
+	}
+	return &int64ObservableCounter{Int64ObservableCounter: i, l: l}, err
+}
+
+type int64ObservableCounter struct {
+	metric.Int64ObservableCounter
+	l *limiter
+}
+
+func (i *int64ObservableCounter) unwrap() (metric.Observable, *limiter) {
+	return i.Int64ObservableCounter, i.l
+}
+
+func (m *limitedMeter) Int64ObservableUpDownCounter(name string, options ...metric.Int64ObservableUpDownCounterOption) (metric.Int64ObservableUpDownCounter, error) {
+	cfg := metric.NewInt64ObservableUpDownCounterConfig(options...)
+	l := m.limiter(name, sdkmetric.InstrumentKindObservableUpDownCounter, cfg.Unit())
+	if l == nil {
+		return m.Meter.Int64ObservableUpDownCounter(name, options...)
+	}
+	i, err := m.Meter.Int64ObservableUpDownCounter(name, int64ObservableOptions[metric.Int64ObservableUpDownCounterOption](l, cfg.Description(), cfg.Unit(), cfg.Callbacks())...)
+	if i == nil {
+		return i, err
+	}
+	return &int64ObservableUpDownCounter{Int64ObservableUpDownCounter: i, l: l}, err
+}
+
+type int64ObservableUpDownCounter struct {
+	metric.Int64ObservableUpDownCounter
+	l *limiter
+}
+
+func (i *int64ObservableUpDownCounter) unwrap() (metric.Observable, *limiter) {
+	return i.Int64ObservableUpDownCounter, i.l
+}
+
+func (m *limitedMeter) Int64ObservableGauge(name string, options ...metric.Int64ObservableGaugeOption) (metric.Int64ObservableGauge, error) {
+	cfg := metric.NewInt64ObservableGaugeConfig(options...)
+	l := m.limiter(name, sdkmetric.InstrumentKindObservableGauge, cfg.Unit())
+	if l == nil {
+		return m.Meter.Int64ObservableGauge(name, options...)
+	}
+	i, err := m.Meter.Int64ObservableGauge(name, int64ObservableOptions[metric.Int64ObservableGaugeOption](l, cfg.Description(), cfg.Unit(), cfg.Callbacks())...)
+	if i == nil {
+		return i, err
+	}
+	return &int64ObservableGauge{Int64ObservableGauge: i, l: l}, err
+}
+
+type int64ObservableGauge struct {
+	metric.Int64ObservableGauge
+	l *limiter
+}
+
+func (i *int64ObservableGauge) unwrap() (metric.Observable, *limiter) {
+	return i.Int64ObservableGauge, i.l
+}
+
+func (m *limitedMeter) Float64ObservableCounter(name string, options ...metric.Float64ObservableCounterOption) (metric.Float64ObservableCounter, error) {
+	cfg := metric.NewFloat64ObservableCounterConfig(options...)
+	l := m.limiter(name, sdkmetric.InstrumentKindObservableCounter, cfg.Unit())
+	if l == nil {
+		return m.Meter.Float64ObservableCounter(name, options...)
+	}
+	i, err := m.Meter.Float64ObservableCounter(name, float64ObservableOptions[metric.Float64ObservableCounterOption](l, cfg.Description(), cfg.Unit(), cfg.Callbacks())...)
+	if i == nil {
+		return i, err
+	}
+	return &float64ObservableCounter{Float64ObservableCounter: i, l: l}, err
+}
+
+type float64ObservableCounter struct {
+	metric.Float64ObservableCounter
+	l *limiter
+}
+
+func (i *float64ObservableCounter) unwrap() (metric.Observable, *limiter) {
+	return i.Float64ObservableCounter, i.l
+}
+
+func (m *limitedMeter) Float64ObservableUpDownCounter(name string, options ...metric.Float64ObservableUpDownCounterOption) (metric.Float64ObservableUpDownCounter, error) {
+	cfg := metric.NewFloat64ObservableUpDownCounterConfig(options...)
+	l := m.limiter(name, sdkmetric.InstrumentKindObservableUpDownCounter, cfg.Unit())
+	if l == nil {
+		return m.Meter.Float64ObservableUpDownCounter(name, options...)
+	}
+	i, err := m.Meter.Float64ObservableUpDownCounter(name, float64ObservableOptions[metric.Float64ObservableUpDownCounterOption](l, cfg.Description(), cfg.Unit(), cfg.Callbacks())...)
+	if i == nil {
+		return i, err
+	}
+	return &float64ObservableUpDownCounter{Float64ObservableUpDownCounter: i, l: l}, err
+}
+
+type float64ObservableUpDownCounter struct {
+	metric.Float64ObservableUpDownCounter
+	l *limiter
+}
+
+func (i *float64ObservableUpDownCounter) unwrap() (metric.Observable, *limiter) {
+	return i.Float64ObservableUpDownCounter, i.l
+}
+
+func (m *limitedMeter) Float64ObservableGauge(name string, options ...metric.Float64ObservableGaugeOption) (metric.Float64ObservableGauge, error) {
+	cfg := metric.NewFloat64ObservableGaugeConfig(options...)
+	l := m.limiter(name, sdkmetric.InstrumentKindObservableGauge, cfg.Unit())
+	if l == nil {
+		return m.Meter.Float64ObservableGauge(name, options...)
+	}
+	i, err := m.Meter.Float64ObservableGauge(name, float64ObservableOptions[metric.Float64ObservableGaugeOption](l, cfg.Description(), cfg.Unit(), cfg.Callbacks())...)
+	if i == nil {
+		return i, err
+	}
+	return &float64ObservableGauge{Float64ObservableGauge: i, l: l}, err
+}
+
+type float64ObservableGauge struct {
+	metric.Float64ObservableGauge
+	l *limiter
+}
+
+func (i *float64ObservableGauge) unwrap() (metric.Observable, *limiter) {
+	return i.Float64ObservableGauge, i.l
+}
+
+// int64ObservableOptions returns options of observable instrument with
+// callbacks that limit observations.
+func int64ObservableOptions[O any](l *limiter, description, unit string, callbacks []metric.Int64Callback) []O {
+	opts := []O{
+		any(metric.WithDescription(description)).(O),
+		any(metric.WithUnit(unit)).(O),
+	}
+	for _, cb := range callbacks {
+		opts = append(opts, any(metric.WithInt64Callback(func(ctx context.Context, o metric.Int64Observer) error {
+			return cb(ctx, &int64Observer{
+				Int64Observer: o,
+				l:             l,
+				seen:          map[attribute.Distinct]struct{}{},
+			})
+		})).(O))
+	}
+	return opts
+}
+
+type int64Observer struct {
+	metric.Int64Observer
+	l    *limiter
+	seen map[attribute.Distinct]struct{}
+}
+
+func (o *int64Observer) Observe(value int64, options ...metric.ObserveOption) {
+	if !o.l.allowObserved(o.seen, metric.NewObserveConfig(options).Attributes()) {
+		options = []metric.ObserveOption{overflowOption}
+	}
+	o.Int64Observer.Observe(value, options...)
+}
+
+// float64ObservableOptions returns options of observable instrument with
+// callbacks that limit observations.
+func float64ObservableOptions[O any](l *limiter, description, unit string, callbacks []metric.Float64Callback) []O {
+	opts := []O{
+		any(metric.WithDescription(description)).(O),
+		any(metric.WithUnit(unit)).(O),
+	}
+	for _, cb := range callbacks {
+		opts = append(opts, any(metric.WithFloat64Callback(func(ctx context.Context, o metric.Float64Observer) error {
+			return cb(ctx, &float64Observer{
+				Float64Observer: o,
+				l:               l,
+				seen:            map[attribute.Distinct]struct{}{},
+			})
+		})).(O))
+	}
+	return opts
+}
+
+type float64Observer struct {
+	metric.Float64Observer
+	l    *limiter
+	seen map[attribute.Distinct]struct{}
+}
+
+func (o *float64Observer) Observe(value float64, options ...metric.ObserveOption) {
+	if !o.l.allowObserved(o.seen, metric.NewObserveConfig(options).Attributes()) {
+		options = []metric.ObserveOption{overflowOption}
+	}
+	o.Float64Observer.Observe(value, options...)
+}
+
+func (m *limitedMeter) RegisterCallback(f metric.Callback, instruments ...metric.Observable) (metric.Registration, error) {
+	unwrapped := make([]metric.Observable, 0, len(instruments))
+	for _, i := range instruments {
+		if u, ok := i.(limitedObservable); ok {
+			i, _ = u.unwrap()
+		}
+		unwrapped = append(unwrapped, i)
+	}
+	return m.Meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		return f(ctx, &limitedObserver{
+			Observer: o,
+			seen:     map[*limiter]map[attribute.Distinct]struct{}{},
+		})
+	}, unwrapped...)
+}
+
+// limitedObserver unwraps limited instruments and limits their observations
+// during single callback.
+type limitedObserver struct {
+	metric.Observer
+	seen map[*limiter]map[attribute.Distinct]struct{}
+}
+
+// unwrap returns instrument to observe and options to observe with.
+func (o *limitedObserver) unwrap(inst metric.Observable, options []metric.ObserveOption) (metric.Observable, []metric.ObserveOption) {
+	u, ok := inst.(limitedObservable)
+	if !ok {
+		return inst, options
+	}
+	inst, l := u.unwrap()
+	seen, ok := o.seen[l]
+	if !ok {
+		seen = map[attribute.Distinct]struct{}{}
+		o.seen[l] = seen
+	}
+	if !l.allowObserved(seen, metric.NewObserveConfig(options).Attributes()) {
+		options = []metric.ObserveOption{overflowOption}
+	}
+	return inst, options
+}
+
+func (o *limitedObserver) ObserveInt64(inst metric.Int64Observable, value int64, options ...metric.ObserveOption) {
+	i, options := o.unwrap(inst, options)
+	if d, ok := i.(metric.Int64Observable); ok {
+		inst = d
+	}
+	o.Observer.ObserveInt64(inst, value, options...)
+}
+
+func (o *limitedObserver) ObserveFloat64(inst metric.Float64Observable, value float64, options ...metric.ObserveOption) {
+	i, options := o.unwrap(inst, options)
+	if d, ok := i.(metric.Float64Observable); ok {
+		inst = d
+	}
+	o.Observer.ObserveFloat64(inst, value, options...)
+}
@@ -0,0 +1,305 @@
+package autometer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/go-faster/sdk/zctx"
+)
+
+func collectMetrics(t *testing.T, r sdkmetric.Reader) map[string]metricdata.Metrics {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, r.Collect(context.Background(), &rm))
+	out := map[string]metricdata.Metrics{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			out[m.Name] = m
+		}
+	}
+	return out
+}
+
+func TestCardinalityLimit(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("OTEL_METRICS_EXPORTER", "manual")
+	t.Setenv(EnvCardinalityLimit, "3")
+	t.Setenv(EnvCardinalityLimits, "special=10, gauge=2")
+
+	reader := sdkmetric.NewManualReader()
+	provider, stop, err := NewMeterProvider(ctx,
+		WithLookupExporter(func(ctx context.Context, name string) (sdkmetric.Reader, bool, error) {
+			return reader, true, nil
+		}),
+		WithViews(sdkmetric.NewView(
+			sdkmetric.Instrument{Name: "filtered"},
+			sdkmetric.Stream{AttributeFilter: attribute.NewDenyKeysFilter("id")},
+		)),
+	)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, stop(ctx)) }()
+
+	meter := provider.Meter("test")
+	counter, err := meter.Int64Counter("requests")
+	require.NoError(t, err)
+	special, err := meter.Float64Histogram("special")
+	require.NoError(t, err)
+	filtered, err := meter.Int64Counter("filtered")
+	require.NoError(t, err)
+	for i := range 5 {
+		id := metric.WithAttributes(attribute.Int("id", i))
+		counter.Add(ctx, 1, id)
+		special.Record(ctx, 1, id)
+		filtered.Add(ctx, 1, id)
+	}
+	// Same instrument shares limit.
+	again, err := meter.Int64Counter("requests")
+	require.NoError(t, err)
+	again.Add(ctx, 1, metric.WithAttributes(attribute.Int("id", 10)))
+
+	gauge, err := meter.Int64ObservableGauge("gauge", metric.WithInt64Callback(func(ctx context.Context, o metric.Int64Observer) error {
+		for i := range 3 {
+			o.Observe(int64(i), metric.WithAttributes(attribute.Int("id", i)))
+		}
+		return nil
+	}))
+	require.NoError(t, err)
+	updown, err := meter.Float64ObservableUpDownCounter("updown")
+	require.NoError(t, err)
+	_, err = meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		for i := range 5 {
+			o.ObserveFloat64(updown, 1, metric.WithAttributes(attribute.Int("id", i)))
+		}
+		return nil
+	}, updown, gauge)
+	require.NoError(t, err)
+
+	for range 2 {
+		metrics := collectMetrics(t, reader)
+
+		sum := metrics["requests"].Data.(metricdata.Sum[int64])
+		require.Len(t, sum.DataPoints, 3)
+		var overflow int64
+		for _, dp := range sum.DataPoints {
+			if isOverflow(dp.Attributes) {
+				overflow = dp.Value
+			}
+		}
+		require.Equal(t, int64(4), overflow)
+
+		require.Len(t, metrics["special"].Data.(metricdata.Histogram[float64]).DataPoints, 5)
+		require.Len(t, metrics["filtered"].Data.(metricdata.Sum[int64]).DataPoints, 1)
+
+		// Asynchronous instruments are limited per callback.
+		g := metrics["gauge"].Data.(metricdata.Gauge[int64])
+		require.Len(t, g.DataPoints, 2)
+		ud := metrics["updown"].Data.(metricdata.Sum[float64])
+		require.Len(t, ud.DataPoints, 3)
+	}
+}
+
+func TestCardinalityLimitDisabled(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("OTEL_METRICS_EXPORTER", "manual")
+	reader := sdkmetric.NewManualReader()
+	provider, stop, err := NewMeterProvider(ctx,
+		WithLookupExporter(func(ctx context.Context, name string) (sdkmetric.Reader, bool, error) {
+			return reader, true, nil
+		}),
+		WithCardinalityLimit(0),
+	)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, stop(ctx)) }()
+	require.IsType(t, &sdkmetric.MeterProvider{}, provider)
+
+	counter, err := provider.Meter("test").Int64Counter("requests")
+	require.NoError(t, err)
+	for i := range defaultCardinalityLimit + 1 {
+		counter.Add(ctx, 1, metric.WithAttributes(attribute.Int("id", i)))
+	}
+	require.Len(t, collectMetrics(t, reader)["requests"].Data.(metricdata.Sum[int64]).DataPoints, defaultCardinalityLimit+1)
+}
+
+func TestCardinalityLimitReset(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		delta    bool
+		expected int
+	}{
+		// Attribute sets are counted since creation.
+		{"Cumulative", false, 1},
+		// Attribute sets are counted since previous collection.
+		{"Delta", true, 2},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			reader := sdkmetric.NewManualReader(sdkmetric.WithTemporalitySelector(func(sdkmetric.InstrumentKind) metricdata.Temporality {
+				return metricdata.DeltaTemporality
+			}))
+			var delta func(sdkmetric.InstrumentKind) bool
+			if tt.delta {
+				delta = deltaKinds([]sdkmetric.TemporalitySelector{func(sdkmetric.InstrumentKind) metricdata.Temporality {
+					return metricdata.DeltaTemporality
+				}})
+			}
+			limits := cardinalityLimits{limit: 10, overrides: map[string]int{"requests": 3}}
+			provider := limitCardinality(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)), limits, nil, delta)
+			require.NotNil(t, provider)
+
+			counter, err := provider.Meter("test").Int64Counter("requests")
+			require.NoError(t, err)
+			for i := range 5 {
+				counter.Add(ctx, 1, metric.WithAttributes(attribute.Int("id", i)))
+			}
+			require.Len(t, collectMetrics(t, reader)["requests"].Data.(metricdata.Sum[int64]).DataPoints, 3)
+			provider.collected()
+
+			// Previously seen attribute set.
+			counter.Add(ctx, 1, metric.WithAttributes(attribute.Int("id", 0)))
+			counter.Add(ctx, 1, metric.WithAttributes(attribute.Int("id", 10)))
+			var recorded int
+			for _, dp := range collectMetrics(t, reader)["requests"].Data.(metricdata.Sum[int64]).DataPoints {
+				if !isOverflow(dp.Attributes) {
+					recorded++
+				}
+			}
+			require.Equal(t, tt.expected, recorded)
+		})
+	}
+}
+
+func TestDeltaKinds(t *testing.T) {
+	delta := temporalitySelector("delta")
+	require.Nil(t, deltaKinds(nil))
+	require.Nil(t, deltaKinds([]sdkmetric.TemporalitySelector{delta, nil}))
+
+	f := deltaKinds([]sdkmetric.TemporalitySelector{delta, delta})
+	require.True(t, f(sdkmetric.InstrumentKindCounter))
+	require.False(t, f(sdkmetric.InstrumentKindUpDownCounter))
+
+	f = deltaKinds([]sdkmetric.TemporalitySelector{delta, temporalitySelector("cumulative")})
+	require.False(t, f(sdkmetric.InstrumentKindCounter))
+}
+
+func TestCardinalityOverflowReport(t *testing.T) {
+	t.Run("Exporter", func(t *testing.T) {
+		core, logs := observer.New(zapcore.WarnLevel)
+		ctx := zctx.Base(context.Background(), zap.New(core))
+		t.Setenv("OTEL_METRICS_EXPORTER", "stdout")
+
+		// Default limit is enforced by SDK.
+		provider, stop, err := NewMeterProvider(ctx, WithWriter(io.Discard))
+		require.NoError(t, err)
+		require.IsType(t, &sdkmetric.MeterProvider{}, provider)
+
+		counter, err := provider.Meter("test").Int64Counter("requests")
+		require.NoError(t, err)
+		for i := range defaultCardinalityLimit + 1 {
+			counter.Add(ctx, 1, metric.WithAttributes(attribute.Int("id", i)))
+		}
+		require.NoError(t, stop(ctx))
+
+		entries := logs.All()
+		require.Len(t, entries, 1)
+		require.Equal(t, map[string]any{"meter": "test", "instrument": "requests"}, entries[0].ContextMap())
+	})
+	t.Run("Prometheus", func(t *testing.T) {
+		core, logs := observer.New(zapcore.WarnLevel)
+		ctx := zctx.Base(context.Background(), zap.New(core))
+		t.Setenv("OTEL_METRICS_EXPORTER", "prometheus")
+		t.Setenv(EnvCardinalityLimit, "3")
+		t.Setenv(EnvCardinalityLimits, "limited=2")
+
+		reg := prometheus.NewRegistry()
+		provider, stop, err := NewMeterProvider(ctx, WithPrometheusRegisterer(reg))
+		require.NoError(t, err)
+		defer func() { require.NoError(t, stop(ctx)) }()
+
+		meter := provider.Meter("test")
+		counter, err := meter.Int64Counter("requests")
+		require.NoError(t, err)
+		limited, err := meter.Int64Counter("limited")
+		require.NoError(t, err)
+		for i := range 5 {
+			counter.Add(ctx, 1, metric.WithAttributes(attribute.Int("id", i)))
+			limited.Add(ctx, 1, metric.WithAttributes(attribute.Int("id", i)))
+		}
+
+		overflows := func() map[string]float64 {
+			families, err := reg.Gather()
+			require.NoError(t, err)
+			out := map[string]float64{}
+			for _, f := range families {
+				if f.GetName() != "otel_sdk_metric_cardinality_overflows_total" {
+					continue
+				}
+				for _, m := range f.GetMetric() {
+					for _, lp := range m.GetLabel() {
+						if lp.GetName() == "instrument" {
+							out[lp.GetValue()] = m.GetCounter().GetValue()
+						}
+					}
+				}
+			}
+			return out
+		}
+		require.Empty(t, overflows())
+		// Reported on previous scrape.
+		require.Equal(t, map[string]float64{"requests_total": 1, "limited_total": 1}, overflows())
+
+		// Warnings are rate-limited per instrument.
+		warned := map[string]int{}
+		for _, e := range logs.All() {
+			warned[fmt.Sprint(e.ContextMap()["instrument"])]++
+		}
+		require.Equal(t, map[string]int{"requests_total": 1, "limited_total": 1}, warned)
+	})
+}
+
+func TestCardinalityFromEnv(t *testing.T) {
+	c, err := cardinalityFromEnv(newConfig(nil))
+	require.NoError(t, err)
+	require.Equal(t, defaultCardinalityLimit, c.limit)
+	require.Equal(t, defaultCardinalityLimit, c.sdk())
+	require.False(t, c.enabled())
+
+	t.Setenv(EnvCardinalityLimit, "100")
+	t.Setenv(EnvCardinalityLimits, "Foo=10,bar=20")
+	c, err = cardinalityFromEnv(newConfig([]Option{
+		WithCardinalityLimit(-1),
+		WithInstrumentCardinalityLimit("bar", 30),
+	}))
+	require.NoError(t, err)
+	require.Equal(t, -1, c.limit)
+	require.Equal(t, 10, c.get("foo"))
+	require.Equal(t, 30, c.get("bar"))
+	require.Equal(t, -1, c.get("baz"))
+	require.Zero(t, c.sdk())
+	require.True(t, c.enabled())
+
+	for _, tt := range []struct {
+		name, value string
+	}{
+		{EnvCardinalityLimit, "many"},
+		{EnvCardinalityLimits, "foo"},
+		{EnvCardinalityLimits, "foo=many"},
+	} {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv(tt.name, tt.value)
+			_, err := cardinalityFromEnv(newConfig(nil))
+			require.ErrorContains(t, err, tt.name)
+		})
+	}
+}
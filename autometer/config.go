@@ -3,10 +3,17 @@ package autometer
 import (
 	"context"
 	"io"
+	"maps"
+	"slices"
 
 	"github.com/prometheus/client_golang/prometheus"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
 	"go.opentelemetry.io/otel/sdk/resource"
+
+	"github.com/go-faster/sdk/otelconfig"
+	"github.com/go-faster/sdk/otelstats"
 )
 
 // config contains configuration options for a MeterProvider.
@@ -17,6 +24,29 @@ type config struct {
 
 	prom         prometheus.Registerer
 	promCallback func(reg *prometheus.Registry)
+	promOptions  []otelprometheus.Option
+	promLegacy   *bool
+
+	exemplarFilter exemplar.Filter
+	views          []sdkmetric.View
+
+	cardinalityLimit  *int
+	cardinalityLimits map[string]int
+
+	file *otelconfig.Config
+
+	stats *otelstats.Stats
+
+	overflows *overflowReporter // set by NewMeterProvider
+}
+
+// metricExporter wraps exporter of periodic reader with given name.
+func (c config) metricExporter(name string, e sdkmetric.Exporter) sdkmetric.Exporter {
+	e = c.stats.MetricExporter(name, e)
+	if c.overflows != nil {
+		e = c.overflows.exporter(e)
+	}
+	return e
 }
 
 // newConfig returns a config configured with options.
@@ -68,6 +98,29 @@ func WithOnPrometheusRegistry(f func(reg *prometheus.Registry)) Option {
 	})
 }
 
+// WithPrometheusOptions adds options of Prometheus exporter, that are
+// applied after options from environment variables or declarative
+// configuration.
+func WithPrometheusOptions(opts ...otelprometheus.Option) Option {
+	return optionFunc(func(conf config) config {
+		conf.promOptions = append(slices.Clone(conf.promOptions), opts...)
+		return conf
+	})
+}
+
+// WithPrometheusLegacyCollectors sets whether process, Go and build info
+// collectors of Prometheus client are registered along with Prometheus
+// exporter.
+//
+// By default, OTEL_EXPORTER_PROMETHEUS_LEGACY_COLLECTORS environment variable
+// is used, collectors are registered if it is not set.
+func WithPrometheusLegacyCollectors(enabled bool) Option {
+	return optionFunc(func(conf config) config {
+		conf.promLegacy = &enabled
+		return conf
+	})
+}
+
 // WithWriter sets writer for the stderr, stdout exporters.
 func WithWriter(out io.Writer) Option {
 	return optionFunc(func(conf config) config {
@@ -77,6 +130,9 @@ func WithWriter(out io.Writer) Option {
 }
 
 // LookupExporter creates exporter by name.
+//
+// Returned reader is used as is: temporality, aggregation, export interval
+// and timeout from environment are not applied to it.
 type LookupExporter func(ctx context.Context, name string) (sdkmetric.Reader, bool, error)
 
 // WithLookupExporter sets exporter lookup function.
@@ -86,3 +142,80 @@ func WithLookupExporter(lookup LookupExporter) Option {
 		return conf
 	})
 }
+
+// WithExemplarFilter sets filter that determines which measurements are offered
+// to exemplar reservoir.
+//
+// By default, OTEL_METRICS_EXEMPLAR_FILTER environment variable is used, which can be
+// "always_on", "always_off" or "trace_based" (default).
+func WithExemplarFilter(filter exemplar.Filter) Option {
+	return optionFunc(func(conf config) config {
+		conf.exemplarFilter = filter
+		return conf
+	})
+}
+
+// WithConfig sets declarative configuration that is used instead of
+// environment variables.
+//
+// By default, configuration is loaded from file set by OTEL_CONFIG_FILE
+// environment variable, if any. Exporters that are not known are created
+// by [LookupExporter].
+func WithConfig(c *otelconfig.Config) Option {
+	return optionFunc(func(conf config) config {
+		conf.file = c
+		return conf
+	})
+}
+
+// WithStats sets statistics collector of exporters and batch processors.
+func WithStats(s *otelstats.Stats) Option {
+	return optionFunc(func(conf config) config {
+		conf.stats = s
+		return conf
+	})
+}
+
+// WithViews adds views that customize metric streams, e.g. rename
+// instruments, drop attributes or change histogram buckets.
+//
+// Views are applied in addition to views from declarative configuration
+// (meter_provider.views) or, if it is not used, from file set by
+// OTEL_METRICS_VIEWS_FILE environment variable. Instrument
+// that matches several views produces stream for each of them.
+func WithViews(views ...sdkmetric.View) Option {
+	return optionFunc(func(conf config) config {
+		conf.views = append(slices.Clone(conf.views), views...)
+		return conf
+	})
+}
+
+// WithCardinalityLimit sets default limit of distinct attribute sets per
+// instrument. Measurements with new attribute sets over limit are recorded
+// to overflow series with "otel.metric.overflow" attribute. Zero or negative
+// value disables limit.
+//
+// By default, OTEL_METRICS_CARDINALITY_LIMIT environment variable is used,
+// or 2000 if it is not set.
+func WithCardinalityLimit(limit int) Option {
+	return optionFunc(func(conf config) config {
+		conf.cardinalityLimit = &limit
+		return conf
+	})
+}
+
+// WithInstrumentCardinalityLimit overrides cardinality limit of instrument
+// with given name, see [WithCardinalityLimit].
+//
+// Overrides from OTEL_METRICS_CARDINALITY_LIMITS environment variable,
+// like "http.server.request.duration=5000", are used for other instruments.
+func WithInstrumentCardinalityLimit(name string, limit int) Option {
+	return optionFunc(func(conf config) config {
+		conf.cardinalityLimits = maps.Clone(conf.cardinalityLimits)
+		if conf.cardinalityLimits == nil {
+			conf.cardinalityLimits = map[string]int{}
+		}
+		conf.cardinalityLimits[name] = limit
+		return conf
+	})
+}
@@ -4,10 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/go-faster/sdk/otelconfig"
 )
 
 func TestWithLookupExporter(t *testing.T) {
@@ -45,3 +51,63 @@ func TestWithLookupExporter(t *testing.T) {
 		})
 	}
 }
+
+func TestWithConfig(t *testing.T) {
+	ctx := context.Background()
+	c, err := otelconfig.Parse([]byte(`file_format: "0.3"
+meter_provider:
+  exemplar_filter: always_off
+  readers:
+    - pull:
+        exporter:
+          manual: {}
+    - periodic:
+        exporter:
+          console: {}
+  views:
+    - selector:
+        instrument_name: requests
+      stream:
+        name: http.requests
+        attribute_keys:
+          included: [method]
+    - selector:
+        instrument_name: "debug.*"
+      stream:
+        aggregation:
+          drop: {}
+`))
+	require.NoError(t, err)
+
+	reader := sdkmetric.NewManualReader()
+	provider, stop, err := NewMeterProvider(ctx,
+		WithConfig(c),
+		WithWriter(io.Discard),
+		WithLookupExporter(func(ctx context.Context, name string) (sdkmetric.Reader, bool, error) {
+			return reader, name == "manual", nil
+		}),
+	)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, stop(ctx)) }()
+
+	meter := provider.Meter("test")
+	requests, err := meter.Int64Counter("requests")
+	require.NoError(t, err)
+	requests.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("method", "GET"),
+		attribute.String("path", "/"),
+	))
+	debug, err := meter.Int64Counter("debug.calls")
+	require.NoError(t, err)
+	debug.Add(ctx, 1)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	metrics := rm.ScopeMetrics[0].Metrics
+	require.Len(t, metrics, 1)
+	require.Equal(t, "http.requests", metrics[0].Name)
+	sum := metrics[0].Data.(metricdata.Sum[int64])
+	require.Len(t, sum.DataPoints, 1)
+	require.Equal(t, attribute.NewSet(attribute.String("method", "GET")), sum.DataPoints[0].Attributes)
+}
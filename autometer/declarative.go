@@ -0,0 +1,402 @@
+package autometer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+
+	"github.com/go-faster/sdk/internal/otlpjson"
+	"github.com/go-faster/sdk/otelconfig"
+	"github.com/go-faster/sdk/zctx"
+)
+
+// newFromConfig creates MeterProvider from declarative configuration.
+func newFromConfig(ctx context.Context, cfg config, c *otelconfig.Config) (
+	meterProvider metric.MeterProvider,
+	meterShutdown ShutdownFunc,
+	rerr error,
+) {
+	mp := c.MeterProvider
+	if c.Disabled || mp == nil {
+		zctx.From(ctx).Debug("Meter provider is not configured, using no-op")
+		return noop.NewMeterProvider(), noopHandler, nil
+	}
+	res, err := c.MergeResource(ctx, cfg.res)
+	if err != nil {
+		return nil, nil, err
+	}
+	var metricOptions []sdkmetric.Option
+	if res != nil {
+		metricOptions = append(metricOptions, sdkmetric.WithResource(res))
+	}
+	filter := cfg.exemplarFilter
+	if v := mp.ExemplarFilter; filter == nil && v != "" {
+		if filter, err = exemplarFilterByName(v); err != nil {
+			return nil, nil, c.Wrap("meter_provider.exemplar_filter", err)
+		}
+	}
+	if filter != nil {
+		metricOptions = append(metricOptions, sdkmetric.WithExemplarFilter(filter))
+	}
+	var views []sdkmetric.View
+	for _, v := range mp.Views {
+		views = append(views, newView(v))
+	}
+	views = append(views, cfg.views...)
+	for _, v := range views {
+		metricOptions = append(metricOptions, sdkmetric.WithView(v))
+	}
+	limits := cardinalityFromConfig(cfg)
+	// Lower limits are enforced by limitCardinality.
+	metricOptions = append(metricOptions, sdkmetric.WithCardinalityLimit(limits.sdk()))
+
+	var readers []sdkmetric.Reader
+	defer func() {
+		if rerr == nil {
+			return
+		}
+		for _, r := range readers {
+			_ = r.Shutdown(ctx)
+		}
+	}()
+	var temporality []sdkmetric.TemporalitySelector
+	for i, r := range mp.Readers {
+		reader, err := newReader(ctx, cfg, c, fmt.Sprintf("meter_provider.readers[%d]", i), r)
+		if err != nil {
+			return nil, nil, err
+		}
+		readers = append(readers, reader)
+		metricOptions = append(metricOptions, sdkmetric.WithReader(reader))
+		temporality = append(temporality, readerTemporality(r))
+	}
+	provider := sdkmetric.NewMeterProvider(metricOptions...)
+	limited, err := wrapProvider(cfg, provider, limits, views, deltaKinds(temporality))
+	if err != nil {
+		_ = provider.Shutdown(ctx)
+		return nil, nil, err
+	}
+	return limited, provider.Shutdown, nil
+}
+
+// readerTemporality returns temporality selector of reader, or nil if it is
+// cumulative or unknown.
+func readerTemporality(r otelconfig.MetricReader) sdkmetric.TemporalitySelector {
+	p := r.Periodic
+	if p == nil {
+		return nil
+	}
+	o, _, ok := p.Exporter.OTLPConfig()
+	if !ok || o.TemporalityPreference == "" {
+		return nil
+	}
+	return temporalitySelector(o.TemporalityPreference)
+}
+
+func newReader(ctx context.Context, cfg config, c *otelconfig.Config, path string, r otelconfig.MetricReader) (sdkmetric.Reader, error) {
+	lg := zctx.From(ctx)
+	if p := r.Pull; p != nil {
+		path := path + ".pull.exporter." + p.Exporter.Name()
+		if p.Exporter.Prometheus != nil {
+			lg.Debug("Using Prometheus metrics exporter")
+			reader, err := newPrometheusReader(cfg, prometheusFromConfig(p.Exporter.Prometheus))
+			if err != nil {
+				return nil, c.Wrap(path, err)
+			}
+			return reader, nil
+		}
+		return lookupReader(ctx, cfg, c, path, p.Exporter.Name())
+	}
+
+	p := r.Periodic
+	path += ".periodic.exporter." + p.Exporter.Name()
+	var opts []sdkmetric.PeriodicReaderOption
+	if v := p.Interval; v != nil {
+		opts = append(opts, sdkmetric.WithInterval(v.Duration()))
+	}
+	if v := p.Timeout; v != nil {
+		opts = append(opts, sdkmetric.WithTimeout(v.Duration()))
+	}
+	if o, proto, ok := p.Exporter.OTLPConfig(); ok {
+		lg.Debug("Using OTLP metrics exporter", zap.String("protocol", proto))
+		switch proto {
+		case otelconfig.ProtocolHTTPProtobuf, otelconfig.ProtocolHTTPJSON:
+			var expOpts []otlpmetrichttp.Option
+			if v := o.Endpoint; strings.Contains(v, "://") {
+				expOpts = append(expOpts, otlpmetrichttp.WithEndpointURL(v))
+			} else if v != "" {
+				expOpts = append(expOpts, otlpmetrichttp.WithEndpoint(v))
+			}
+			if o.Insecure {
+				expOpts = append(expOpts, otlpmetrichttp.WithInsecure())
+			}
+			if h := o.HeaderMap(); len(h) > 0 {
+				expOpts = append(expOpts, otlpmetrichttp.WithHeaders(h))
+			}
+			if o.Compression == "gzip" {
+				expOpts = append(expOpts, otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression))
+			}
+			if v := o.Timeout; v != nil {
+				expOpts = append(expOpts, otlpmetrichttp.WithTimeout(v.Duration()))
+			}
+			if v := o.TemporalityPreference; v != "" {
+				expOpts = append(expOpts, otlpmetrichttp.WithTemporalitySelector(temporalitySelector(v)))
+			}
+			if v := o.DefaultHistogramAggregation; v != "" {
+				expOpts = append(expOpts, otlpmetrichttp.WithAggregationSelector(histogramAggregationSelector(v)))
+			}
+			if proto == otelconfig.ProtocolHTTPJSON {
+				client, err := otlpjson.ConfigFromEnv(otlpjson.Metrics)
+				if err != nil {
+					return nil, c.Wrap(path, errors.Wrap(err, "configure OTLP JSON client"))
+				}
+				if v := o.Timeout; v != nil {
+					client.Timeout = v.Duration()
+				}
+				expOpts = append(expOpts, otlpmetrichttp.WithHTTPClient(otlpjson.NewClient(otlpjson.Metrics, client)))
+			}
+			exp, err := otlpmetrichttp.New(ctx, expOpts...)
+			if err != nil {
+				return nil, c.Wrap(path, errors.Wrap(err, "create OTLP HTTP metric exporter"))
+			}
+			return sdkmetric.NewPeriodicReader(cfg.metricExporter(p.Exporter.Name(), exp), opts...), nil
+		case otelconfig.ProtocolGRPC:
+			var expOpts []otlpmetricgrpc.Option
+			if v := o.Endpoint; strings.Contains(v, "://") {
+				expOpts = append(expOpts, otlpmetricgrpc.WithEndpointURL(v))
+			} else if v != "" {
+				expOpts = append(expOpts, otlpmetricgrpc.WithEndpoint(v))
+			}
+			if o.Insecure {
+				expOpts = append(expOpts, otlpmetricgrpc.WithInsecure())
+			}
+			if h := o.HeaderMap(); len(h) > 0 {
+				expOpts = append(expOpts, otlpmetricgrpc.WithHeaders(h))
+			}
+			if o.Compression == "gzip" {
+				expOpts = append(expOpts, otlpmetricgrpc.WithCompressor("gzip"))
+			}
+			if v := o.Timeout; v != nil {
+				expOpts = append(expOpts, otlpmetricgrpc.WithTimeout(v.Duration()))
+			}
+			if v := o.TemporalityPreference; v != "" {
+				expOpts = append(expOpts, otlpmetricgrpc.WithTemporalitySelector(temporalitySelector(v)))
+			}
+			if v := o.DefaultHistogramAggregation; v != "" {
+				expOpts = append(expOpts, otlpmetricgrpc.WithAggregationSelector(histogramAggregationSelector(v)))
+			}
+			exp, err := otlpmetricgrpc.New(ctx, expOpts...)
+			if err != nil {
+				return nil, c.Wrap(path, errors.Wrap(err, "create OTLP gRPC metric exporter"))
+			}
+			return sdkmetric.NewPeriodicReader(cfg.metricExporter(p.Exporter.Name(), exp), opts...), nil
+		default:
+			return nil, c.Errorf(path, "unsupported metric OTLP protocol %q", proto)
+		}
+	}
+	if p.Exporter.Console != nil {
+		lg.Debug("Using console metrics exporter")
+		writer := cfg.writer
+		if writer == nil {
+			writer = os.Stdout
+		}
+		exp, err := stdoutmetric.New(stdoutmetric.WithEncoder(json.NewEncoder(writer)))
+		if err != nil {
+			return nil, c.Wrap(path, errors.Wrap(err, "create console metric exporter"))
+		}
+		return sdkmetric.NewPeriodicReader(cfg.metricExporter(p.Exporter.Name(), exp), opts...), nil
+	}
+	return lookupReader(ctx, cfg, c, path, p.Exporter.Name())
+}
+
+func lookupReader(ctx context.Context, cfg config, c *otelconfig.Config, path, name string) (sdkmetric.Reader, error) {
+	if lookup := cfg.lookup; lookup != nil {
+		lg := zctx.From(ctx)
+		lg.Debug("Looking for metrics exporter", zap.String("exporter", name))
+		r, ok, err := lookup(ctx, name)
+		if err != nil {
+			return nil, c.Wrap(path, errors.Wrapf(err, "create %q", name))
+		}
+		if ok {
+			lg.Debug("Using user-defined metrics exporter", zap.String("exporter", name))
+			return r, nil
+		}
+	}
+	return nil, c.Errorf(path, "unsupported metrics exporter %q", name)
+}
+
+// temporalitySelector returns selector for temporality preference.
+//
+// See OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE.
+func temporalitySelector(preference string) sdkmetric.TemporalitySelector {
+	switch preference {
+	case "delta":
+		return func(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+			switch kind {
+			case sdkmetric.InstrumentKindCounter,
+				sdkmetric.InstrumentKindObservableCounter,
+				sdkmetric.InstrumentKindHistogram:
+				return metricdata.DeltaTemporality
+			default:
+				return metricdata.CumulativeTemporality
+			}
+		}
+	case "low_memory":
+		return func(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+			switch kind {
+			case sdkmetric.InstrumentKindCounter,
+				sdkmetric.InstrumentKindHistogram:
+				return metricdata.DeltaTemporality
+			default:
+				return metricdata.CumulativeTemporality
+			}
+		}
+	default:
+		return sdkmetric.DefaultTemporalitySelector
+	}
+}
+
+// histogramAggregationSelector returns selector with default histogram aggregation.
+//
+// See OTEL_EXPORTER_OTLP_METRICS_DEFAULT_HISTOGRAM_AGGREGATION.
+func histogramAggregationSelector(name string) sdkmetric.AggregationSelector {
+	if name != "base2_exponential_bucket_histogram" {
+		return sdkmetric.DefaultAggregationSelector
+	}
+	return func(kind sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+		if kind == sdkmetric.InstrumentKindHistogram {
+			return sdkmetric.AggregationBase2ExponentialHistogram{
+				MaxSize:  160,
+				MaxScale: 20,
+			}
+		}
+		return sdkmetric.DefaultAggregationSelector(kind)
+	}
+}
+
+var instrumentKinds = map[string]sdkmetric.InstrumentKind{
+	"counter":                    sdkmetric.InstrumentKindCounter,
+	"up_down_counter":            sdkmetric.InstrumentKindUpDownCounter,
+	"histogram":                  sdkmetric.InstrumentKindHistogram,
+	"gauge":                      sdkmetric.InstrumentKindGauge,
+	"observable_counter":         sdkmetric.InstrumentKindObservableCounter,
+	"observable_up_down_counter": sdkmetric.InstrumentKindObservableUpDownCounter,
+	"observable_gauge":           sdkmetric.InstrumentKindObservableGauge,
+}
+
+// defaultHistogramBoundaries are explicit bucket histogram boundaries defined by specification.
+var defaultHistogramBoundaries = []float64{
+	0, 5, 10, 25, 50, 75, 100, 250, 500, 750, 1000, 2500, 5000, 7500, 10000,
+}
+
+func newView(v otelconfig.View) sdkmetric.View {
+	var (
+		sel    = v.Selector
+		stream = v.Stream
+	)
+	inst := sdkmetric.Instrument{
+		Name: sel.InstrumentName,
+		Kind: instrumentKinds[sel.InstrumentType],
+		Unit: sel.Unit,
+		Scope: instrumentation.Scope{
+			Name:      sel.MeterName,
+			Version:   sel.MeterVersion,
+			SchemaURL: sel.MeterSchemaURL,
+		},
+	}
+	s := sdkmetric.Stream{
+		Name:        stream.Name,
+		Description: stream.Description,
+	}
+	if a := stream.Aggregation; a != nil {
+		s.Aggregation = newAggregation(a)
+	}
+	if k := stream.AttributeKeys; k != nil {
+		s.AttributeFilter = newAttributeFilter(k)
+	}
+	return sdkmetric.NewView(inst, s)
+}
+
+func newAggregation(a *otelconfig.Aggregation) sdkmetric.Aggregation {
+	switch {
+	case a.Drop != nil:
+		return sdkmetric.AggregationDrop{}
+	case a.Sum != nil:
+		return sdkmetric.AggregationSum{}
+	case a.LastValue != nil:
+		return sdkmetric.AggregationLastValue{}
+	case a.ExplicitBucketHistogram != nil:
+		h := a.ExplicitBucketHistogram
+		agg := sdkmetric.AggregationExplicitBucketHistogram{
+			Boundaries: h.Boundaries,
+		}
+		if agg.Boundaries == nil {
+			agg.Boundaries = defaultHistogramBoundaries
+		}
+		if v := h.RecordMinMax; v != nil {
+			agg.NoMinMax = !*v
+		}
+		return agg
+	case a.Base2ExponentialBucketHistogram != nil:
+		h := a.Base2ExponentialBucketHistogram
+		agg := sdkmetric.AggregationBase2ExponentialHistogram{
+			MaxSize:  160,
+			MaxScale: 20,
+		}
+		if v := h.MaxSize; v != nil {
+			agg.MaxSize = int32(*v)
+		}
+		if v := h.MaxScale; v != nil {
+			agg.MaxScale = int32(*v)
+		}
+		if v := h.RecordMinMax; v != nil {
+			agg.NoMinMax = !*v
+		}
+		return agg
+	default:
+		return sdkmetric.AggregationDefault{}
+	}
+}
+
+func newAttributeFilter(k *otelconfig.IncludeExclude) attribute.Filter {
+	var (
+		include attribute.Filter
+		exclude attribute.Filter
+	)
+	if k.Included != nil {
+		keys := make([]attribute.Key, 0, len(k.Included))
+		for _, v := range k.Included {
+			keys = append(keys, attribute.Key(v))
+		}
+		include = attribute.NewAllowKeysFilter(keys...)
+	}
+	if len(k.Excluded) > 0 {
+		keys := make([]attribute.Key, 0, len(k.Excluded))
+		for _, v := range k.Excluded {
+			keys = append(keys, attribute.Key(v))
+		}
+		exclude = attribute.NewDenyKeysFilter(keys...)
+	}
+	return func(kv attribute.KeyValue) bool {
+		if include != nil && !include(kv) {
+			return false
+		}
+		if exclude != nil && !exclude(kv) {
+			return false
+		}
+		return true
+	}
+}
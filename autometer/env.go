@@ -0,0 +1,99 @@
+package autometer
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-faster/errors"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+
+	"github.com/go-faster/sdk/otelconfig"
+)
+
+// EnvViewsFile is environment variable with path to metric views file.
+//
+// See [otelconfig.LoadViews] for format.
+const EnvViewsFile = "OTEL_METRICS_VIEWS_FILE"
+
+// readerEnv is configuration of periodic readers and exporters from
+// environment variables.
+//
+// Exporters read same variables on their own, but silently ignore
+// invalid values, so variables are parsed and applied explicitly.
+type readerEnv struct {
+	periodic    []sdkmetric.PeriodicReaderOption
+	temporality sdkmetric.TemporalitySelector // nil if not set
+	aggregation sdkmetric.AggregationSelector // nil if not set
+}
+
+func parseReaderEnv() (readerEnv, error) {
+	var r readerEnv
+	if d, ok, err := parseMillis("OTEL_METRIC_EXPORT_INTERVAL"); err != nil {
+		return r, err
+	} else if ok {
+		r.periodic = append(r.periodic, sdkmetric.WithInterval(d))
+	}
+	if d, ok, err := parseMillis("OTEL_METRIC_EXPORT_TIMEOUT"); err != nil {
+		return r, err
+	} else if ok {
+		r.periodic = append(r.periodic, sdkmetric.WithTimeout(d))
+	}
+
+	const temporalityEnv = "OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE"
+	if v := os.Getenv(temporalityEnv); v != "" {
+		switch p := strings.ToLower(strings.TrimSpace(v)); p {
+		case "cumulative", "delta":
+			r.temporality = temporalitySelector(p)
+		case "lowmemory", "low_memory":
+			r.temporality = temporalitySelector("low_memory")
+		default:
+			return r, errors.Errorf("unsupported %s %q", temporalityEnv, v)
+		}
+	}
+
+	const aggregationEnv = "OTEL_EXPORTER_OTLP_METRICS_DEFAULT_HISTOGRAM_AGGREGATION"
+	if v := os.Getenv(aggregationEnv); v != "" {
+		switch a := strings.ToLower(strings.TrimSpace(v)); a {
+		case "explicit_bucket_histogram", "base2_exponential_bucket_histogram":
+			r.aggregation = histogramAggregationSelector(a)
+		default:
+			return r, errors.Errorf("unsupported %s %q", aggregationEnv, v)
+		}
+	}
+	return r, nil
+}
+
+// parseMillis parses positive duration in milliseconds from environment variable.
+func parseMillis(name string) (time.Duration, bool, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return 0, false, nil
+	}
+	ms, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "parse %s", name)
+	}
+	if ms <= 0 {
+		return 0, false, errors.Errorf("invalid %s %q: must be positive", name, v)
+	}
+	return time.Duration(ms) * time.Millisecond, true, nil
+}
+
+// viewsFromEnv loads views from file set by [EnvViewsFile], if any.
+func viewsFromEnv() ([]sdkmetric.View, error) {
+	name := os.Getenv(EnvViewsFile)
+	if name == "" {
+		return nil, nil
+	}
+	views, err := otelconfig.LoadViews(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "load %s", EnvViewsFile)
+	}
+	out := make([]sdkmetric.View, 0, len(views))
+	for _, v := range views {
+		out = append(out, newView(v))
+	}
+	return out, nil
+}
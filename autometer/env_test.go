@@ -0,0 +1,32 @@
+package autometer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestParseReaderEnv(t *testing.T) {
+	r, err := parseReaderEnv()
+	require.NoError(t, err)
+	require.Empty(t, r.periodic)
+	require.Nil(t, r.temporality)
+	require.Nil(t, r.aggregation)
+
+	t.Setenv("OTEL_METRIC_EXPORT_INTERVAL", "1000")
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE", "delta")
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_DEFAULT_HISTOGRAM_AGGREGATION", "base2_exponential_bucket_histogram")
+	r, err = parseReaderEnv()
+	require.NoError(t, err)
+	require.Len(t, r.periodic, 1)
+	require.Equal(t, metricdata.DeltaTemporality, r.temporality(sdkmetric.InstrumentKindCounter))
+	require.Equal(t, metricdata.CumulativeTemporality, r.temporality(sdkmetric.InstrumentKindUpDownCounter))
+	require.IsType(t, sdkmetric.AggregationBase2ExponentialHistogram{}, r.aggregation(sdkmetric.InstrumentKindHistogram))
+
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE", "lowmemory")
+	r, err = parseReaderEnv()
+	require.NoError(t, err)
+	require.Equal(t, metricdata.CumulativeTemporality, r.temporality(sdkmetric.InstrumentKindObservableCounter))
+}
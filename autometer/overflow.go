@@ -0,0 +1,222 @@
+package autometer
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+)
+
+// overflowWarnInterval is minimal interval between overflow warnings of
+// single instrument.
+const overflowWarnInterval = time.Minute
+
+// overflowReporter reports instruments with overflow series in collected
+// metrics, whether limited by SDK or by [limitCardinality].
+//
+// Metrics are inspected on export or scrape, so measurements have no
+// overhead. Readers returned by exporter lookup are not inspected.
+type overflowReporter struct {
+	lg *zap.Logger
+
+	mux       sync.Mutex
+	overflows metric.Int64Counter // nil until provider is created
+	limited   *limitedProvider
+	warned    map[instrumentKey]time.Time
+}
+
+type instrumentKey struct {
+	meter      string
+	instrument string
+}
+
+func newOverflowReporter(lg *zap.Logger) *overflowReporter {
+	return &overflowReporter{
+		lg:     lg,
+		warned: map[instrumentKey]time.Time{},
+	}
+}
+
+// init sets provider to report overflows to and limited provider to notify
+// about collections.
+func (r *overflowReporter) init(mp metric.MeterProvider, limited *limitedProvider) error {
+	overflows, err := mp.Meter(instrumentationName).Int64Counter("otel.sdk.metric.cardinality.overflows",
+		metric.WithDescription("Number of collections of instrument with overflow series due to cardinality limit"),
+		metric.WithUnit("{collection}"),
+	)
+	if err != nil {
+		return err
+	}
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.overflows = overflows
+	r.limited = limited
+	return nil
+}
+
+// overflow reports that instrument has overflow series in collection.
+func (r *overflowReporter) overflow(ctx context.Context, meter, instrument string) {
+	k := instrumentKey{meter: meter, instrument: instrument}
+	now := time.Now()
+
+	r.mux.Lock()
+	overflows := r.overflows
+	last, warned := r.warned[k]
+	warn := !warned || now.Sub(last) >= overflowWarnInterval
+	if warn {
+		r.warned[k] = now
+	}
+	r.mux.Unlock()
+
+	if overflows != nil {
+		overflows.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("meter", meter),
+			attribute.String("instrument", instrument),
+		))
+	}
+	if warn {
+		r.lg.Warn("Metric cardinality limit reached, recording to overflow series",
+			zap.String("meter", meter),
+			zap.String("instrument", instrument),
+		)
+	}
+}
+
+// collected notifies limited provider about completed collection.
+func (r *overflowReporter) collected() {
+	r.mux.Lock()
+	limited := r.limited
+	r.mux.Unlock()
+	limited.collected()
+}
+
+// report reports instruments with overflow series in rm.
+func (r *overflowReporter) report(ctx context.Context, rm *metricdata.ResourceMetrics) {
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if hasOverflowSeries(m.Data) {
+				r.overflow(ctx, sm.Scope.Name, m.Name)
+			}
+		}
+	}
+	r.collected()
+}
+
+func isOverflow(set attribute.Set) bool {
+	v, ok := set.Value(overflowKey)
+	return ok && v.AsBool()
+}
+
+func anyOverflow[P any](points []P, attrs func(*P) attribute.Set) bool {
+	for i := range points {
+		if isOverflow(attrs(&points[i])) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasOverflowSeries(data metricdata.Aggregation) bool {
+	switch d := data.(type) {
+	case metricdata.Sum[int64]:
+		return anyOverflow(d.DataPoints, func(p *metricdata.DataPoint[int64]) attribute.Set { return p.Attributes })
+	case metricdata.Sum[float64]:
+		return anyOverflow(d.DataPoints, func(p *metricdata.DataPoint[float64]) attribute.Set { return p.Attributes })
+	case metricdata.Gauge[int64]:
+		return anyOverflow(d.DataPoints, func(p *metricdata.DataPoint[int64]) attribute.Set { return p.Attributes })
+	case metricdata.Gauge[float64]:
+		return anyOverflow(d.DataPoints, func(p *metricdata.DataPoint[float64]) attribute.Set { return p.Attributes })
+	case metricdata.Histogram[int64]:
+		return anyOverflow(d.DataPoints, func(p *metricdata.HistogramDataPoint[int64]) attribute.Set { return p.Attributes })
+	case metricdata.Histogram[float64]:
+		return anyOverflow(d.DataPoints, func(p *metricdata.HistogramDataPoint[float64]) attribute.Set { return p.Attributes })
+	case metricdata.ExponentialHistogram[int64]:
+		return anyOverflow(d.DataPoints, func(p *metricdata.ExponentialHistogramDataPoint[int64]) attribute.Set { return p.Attributes })
+	case metricdata.ExponentialHistogram[float64]:
+		return anyOverflow(d.DataPoints, func(p *metricdata.ExponentialHistogramDataPoint[float64]) attribute.Set { return p.Attributes })
+	default:
+		return false
+	}
+}
+
+// exporter wraps metric exporter to report overflows before export.
+func (r *overflowReporter) exporter(e sdkmetric.Exporter) sdkmetric.Exporter {
+	return &overflowExporter{Exporter: e, r: r}
+}
+
+type overflowExporter struct {
+	sdkmetric.Exporter
+	r *overflowReporter
+}
+
+// Export implements [sdkmetric.Exporter].
+func (e *overflowExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	e.r.report(ctx, rm)
+	return e.Exporter.Export(ctx, rm)
+}
+
+// reportPrometheus reports metrics of single scrape with overflow label,
+// forwarding them to ch.
+func (r *overflowReporter) reportPrometheus(in <-chan prometheus.Metric, ch chan<- prometheus.Metric) {
+	var (
+		pb   dto.Metric
+		seen map[instrumentKey]struct{}
+	)
+	for m := range in {
+		ch <- m
+		pb.Reset()
+		if err := m.Write(&pb); err != nil {
+			continue
+		}
+		var (
+			overflow bool
+			meter    string
+		)
+		for _, lp := range pb.GetLabel() {
+			// Label names are translated depending on strategy.
+			switch lp.GetName() {
+			case "otel_scope_name":
+				meter = lp.GetValue()
+			case "otel_metric_overflow", string(overflowKey):
+				overflow = lp.GetValue() == "true"
+			}
+		}
+		if !overflow {
+			continue
+		}
+		k := instrumentKey{meter: meter, instrument: promName(m.Desc())}
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		if seen == nil {
+			seen = map[instrumentKey]struct{}{}
+		}
+		seen[k] = struct{}{}
+		r.overflow(context.Background(), k.meter, k.instrument)
+	}
+	r.collected()
+}
+
+// promName returns fully-qualified name of metric, which is only
+// available from string representation of descriptor.
+func promName(d *prometheus.Desc) string {
+	_, s, ok := strings.Cut(d.String(), "fqName: ")
+	if !ok {
+		return ""
+	}
+	q, err := strconv.QuotedPrefix(s)
+	if err != nil {
+		return ""
+	}
+	name, _ := strconv.Unquote(q)
+	return name
+}
@@ -0,0 +1,272 @@
+package autometer
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-faster/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/otlptranslator"
+	"go.opentelemetry.io/otel/attribute"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+
+	"github.com/go-faster/sdk/otelconfig"
+)
+
+// defaultTranslationStrategy is set explicitly, so metric names do not
+// change with exporter defaults.
+var defaultTranslationStrategy = otlptranslator.UnderscoreEscapingWithSuffixes
+
+// prometheusConfig configures Prometheus exporter.
+type prometheusConfig struct {
+	options []otelprometheus.Option
+	legacy  bool // register legacy collectors
+}
+
+func translationStrategy(v string) (otlptranslator.TranslationStrategyOption, error) {
+	switch s := otlptranslator.TranslationStrategyOption(v); s {
+	case otlptranslator.UnderscoreEscapingWithSuffixes,
+		otlptranslator.UnderscoreEscapingWithoutSuffixes,
+		otlptranslator.NoUTF8EscapingWithSuffixes,
+		otlptranslator.NoTranslation:
+		return s, nil
+	default:
+		return "", errors.Errorf("unsupported translation strategy %q", v)
+	}
+}
+
+func parseBoolEnv(name string) (value, ok bool, err error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return false, false, nil
+	}
+	value, err = strconv.ParseBool(strings.TrimSpace(v))
+	if err != nil {
+		return false, false, errors.Wrapf(err, "parse %s", name)
+	}
+	return value, true, nil
+}
+
+// prometheusFromEnv returns Prometheus exporter configuration from
+// OTEL_EXPORTER_PROMETHEUS_* environment variables.
+func prometheusFromEnv() (prometheusConfig, error) {
+	p := prometheusConfig{legacy: true}
+
+	strategy := defaultTranslationStrategy
+	if v := os.Getenv("OTEL_EXPORTER_PROMETHEUS_TRANSLATION_STRATEGY"); v != "" {
+		s, err := translationStrategy(strings.TrimSpace(v))
+		if err != nil {
+			return p, errors.Wrap(err, "parse OTEL_EXPORTER_PROMETHEUS_TRANSLATION_STRATEGY")
+		}
+		strategy = s
+	}
+	p.options = append(p.options, otelprometheus.WithTranslationStrategy(strategy))
+	if v := os.Getenv("OTEL_EXPORTER_PROMETHEUS_NAMESPACE"); v != "" {
+		p.options = append(p.options, otelprometheus.WithNamespace(v))
+	}
+	for _, flag := range []struct {
+		name   string
+		option otelprometheus.Option
+	}{
+		{"OTEL_EXPORTER_PROMETHEUS_WITHOUT_SCOPE_INFO", otelprometheus.WithoutScopeInfo()},
+		{"OTEL_EXPORTER_PROMETHEUS_WITHOUT_TARGET_INFO", otelprometheus.WithoutTargetInfo()},
+	} {
+		v, _, err := parseBoolEnv(flag.name)
+		if err != nil {
+			return p, err
+		}
+		if v {
+			p.options = append(p.options, flag.option)
+		}
+	}
+	if v := os.Getenv("OTEL_EXPORTER_PROMETHEUS_RESOURCE_CONSTANT_LABELS"); v != "" {
+		var keys []attribute.Key
+		for key := range strings.SplitSeq(v, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				keys = append(keys, attribute.Key(key))
+			}
+		}
+		p.options = append(p.options, otelprometheus.WithResourceAsConstantLabels(attribute.NewAllowKeysFilter(keys...)))
+	}
+	v, ok, err := parseBoolEnv("OTEL_EXPORTER_PROMETHEUS_LEGACY_COLLECTORS")
+	if err != nil {
+		return p, err
+	}
+	if ok {
+		p.legacy = v
+	}
+	return p, nil
+}
+
+// prometheusFromConfig returns Prometheus exporter configuration from
+// validated declarative configuration.
+func prometheusFromConfig(c *otelconfig.Prometheus) prometheusConfig {
+	p := prometheusConfig{legacy: true}
+
+	strategy := defaultTranslationStrategy
+	if v := c.TranslationStrategy; v != "" {
+		strategy = otlptranslator.TranslationStrategyOption(v)
+	}
+	p.options = append(p.options, otelprometheus.WithTranslationStrategy(strategy))
+	if c.Namespace != "" {
+		p.options = append(p.options, otelprometheus.WithNamespace(c.Namespace))
+	}
+	if c.WithoutScopeInfo {
+		p.options = append(p.options, otelprometheus.WithoutScopeInfo())
+	}
+	if c.WithoutTargetInfo {
+		p.options = append(p.options, otelprometheus.WithoutTargetInfo())
+	}
+	if k := c.WithResourceConstantLabels; k != nil {
+		p.options = append(p.options, otelprometheus.WithResourceAsConstantLabels(newAttributeFilter(k)))
+	}
+	if v := c.LegacyCollectors; v != nil {
+		p.legacy = *v
+	}
+	return p
+}
+
+// newPrometheusReader creates Prometheus exporter registered in configured registry.
+//
+// Default registry is not pedantic, as pedantic checks are intended for
+// tests and fail whole scrape on single inconsistent collector.
+//
+// Exporter collector is unregistered on reader shutdown, so registry can be
+// shared by meter providers re-created on reload. Legacy collectors are
+// registered once per registry.
+func newPrometheusReader(cfg config, p prometheusConfig) (sdkmetric.Reader, error) {
+	reg := cfg.prom
+	if reg == nil {
+		reg = prometheus.NewRegistry()
+	}
+	if cfg.promCallback != nil {
+		switch v := reg.(type) {
+		case *prometheus.Registry:
+			cfg.promCallback(v)
+		}
+	}
+	legacy := p.legacy
+	if cfg.promLegacy != nil {
+		legacy = *cfg.promLegacy
+	}
+	if legacy {
+		// Register legacy prometheus-only runtime metrics for backward compatibility.
+		for _, c := range []prometheus.Collector{
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+			collectors.NewGoCollector(),
+			collectors.NewBuildInfoCollector(),
+		} {
+			if err := reg.Register(c); err != nil {
+				if errors.As(err, new(prometheus.AlreadyRegisteredError)) {
+					continue
+				}
+				return nil, errors.Wrap(err, "register legacy collector")
+			}
+		}
+	}
+
+	wrapped := &promRegisterer{Registerer: reg, overflows: cfg.overflows}
+	opts := []otelprometheus.Option{
+		otelprometheus.WithRegisterer(wrapped),
+	}
+	opts = append(opts, p.options...)
+	opts = append(opts, cfg.promOptions...)
+	exp, err := otelprometheus.New(opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "create Prometheus exporter")
+	}
+	return &promReader{Reader: exp, reg: wrapped}, nil
+}
+
+// promReader is Prometheus exporter that unregisters its collectors on shutdown.
+type promReader struct {
+	sdkmetric.Reader
+	reg *promRegisterer
+}
+
+// Shutdown implements [sdkmetric.Reader].
+func (r *promReader) Shutdown(ctx context.Context) error {
+	defer r.reg.unregister()
+	return r.Reader.Shutdown(ctx)
+}
+
+// promRegisterer registers collectors that can be detached, as unchecked
+// collectors can't be unregistered from [prometheus.Registry].
+type promRegisterer struct {
+	prometheus.Registerer
+	overflows *overflowReporter // optional
+
+	mux        sync.Mutex
+	registered []*promCollector
+}
+
+// Register implements [prometheus.Registerer].
+func (r *promRegisterer) Register(c prometheus.Collector) error {
+	w := &promCollector{overflows: r.overflows}
+	w.c.Store(&c)
+	if err := r.Registerer.Register(w); err != nil {
+		return err
+	}
+	r.mux.Lock()
+	r.registered = append(r.registered, w)
+	r.mux.Unlock()
+	return nil
+}
+
+// MustRegister implements [prometheus.Registerer].
+func (r *promRegisterer) MustRegister(cs ...prometheus.Collector) {
+	for _, c := range cs {
+		if err := r.Register(c); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// unregister unregisters and detaches registered collectors.
+func (r *promRegisterer) unregister() {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	for _, c := range r.registered {
+		r.Registerer.Unregister(c)
+		c.c.Store(nil)
+	}
+	r.registered = nil
+}
+
+// promCollector is detachable collector that reports overflows of
+// collected metrics.
+type promCollector struct {
+	c         atomic.Pointer[prometheus.Collector]
+	overflows *overflowReporter // optional
+}
+
+// Describe implements [prometheus.Collector].
+func (w *promCollector) Describe(ch chan<- *prometheus.Desc) {
+	if c := w.c.Load(); c != nil {
+		(*c).Describe(ch)
+	}
+}
+
+// Collect implements [prometheus.Collector].
+func (w *promCollector) Collect(ch chan<- prometheus.Metric) {
+	c := w.c.Load()
+	if c == nil {
+		return
+	}
+	if w.overflows == nil {
+		(*c).Collect(ch)
+		return
+	}
+	in := make(chan prometheus.Metric)
+	go func() {
+		defer close(in)
+		(*c).Collect(in)
+	}()
+	w.overflows.reportPrometheus(in, ch)
+}
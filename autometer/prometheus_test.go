@@ -0,0 +1,145 @@
+package autometer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	"github.com/go-faster/sdk/autometer"
+	"github.com/go-faster/sdk/otelconfig"
+)
+
+func gatherPrometheus(t *testing.T, reg *prometheus.Registry, options ...autometer.Option) map[string]*dto.MetricFamily {
+	t.Helper()
+	ctx := context.Background()
+	res := resource.NewSchemaless(attribute.String("service.name", "api"))
+	options = append([]autometer.Option{
+		autometer.WithResource(res),
+		autometer.WithPrometheusRegisterer(reg),
+	}, options...)
+	provider, stop, err := autometer.NewMeterProvider(ctx, options...)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, stop(ctx)) }()
+
+	counter, err := provider.Meter("test").Int64Counter("requests", metric.WithUnit("s"))
+	require.NoError(t, err)
+	counter.Add(ctx, 1)
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	out := map[string]*dto.MetricFamily{}
+	for _, f := range families {
+		out[f.GetName()] = f
+	}
+	return out
+}
+
+func labelNames(f *dto.MetricFamily) []string {
+	var names []string
+	for _, l := range f.GetMetric()[0].GetLabel() {
+		names = append(names, l.GetName())
+	}
+	return names
+}
+
+// undescribedCollector collects metric with descriptor that is not described.
+type undescribedCollector struct{}
+
+func (undescribedCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- prometheus.NewDesc("described", "Described metric.", nil, nil)
+}
+
+func (undescribedCollector) Collect(ch chan<- prometheus.Metric) {
+	desc := prometheus.NewDesc("undescribed", "Undescribed metric.", nil, nil)
+	ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, 1)
+}
+
+func TestPrometheusExporter(t *testing.T) {
+	t.Setenv("OTEL_METRICS_EXPORTER", "prometheus")
+	t.Run("Default", func(t *testing.T) {
+		families := gatherPrometheus(t, prometheus.NewRegistry())
+		require.Contains(t, families, "requests_seconds_total")
+		require.Contains(t, families, "target_info")
+		require.Contains(t, families, "go_goroutines")
+		require.Contains(t, labelNames(families["requests_seconds_total"]), "otel_scope_name")
+	})
+	t.Run("Env", func(t *testing.T) {
+		t.Setenv("OTEL_EXPORTER_PROMETHEUS_NAMESPACE", "svc")
+		t.Setenv("OTEL_EXPORTER_PROMETHEUS_TRANSLATION_STRATEGY", "UnderscoreEscapingWithoutSuffixes")
+		t.Setenv("OTEL_EXPORTER_PROMETHEUS_WITHOUT_SCOPE_INFO", "true")
+		t.Setenv("OTEL_EXPORTER_PROMETHEUS_WITHOUT_TARGET_INFO", "true")
+		t.Setenv("OTEL_EXPORTER_PROMETHEUS_RESOURCE_CONSTANT_LABELS", "service.name")
+		t.Setenv("OTEL_EXPORTER_PROMETHEUS_LEGACY_COLLECTORS", "false")
+		families := gatherPrometheus(t, prometheus.NewRegistry())
+		require.Len(t, families, 1)
+		require.Contains(t, families, "svc_requests")
+		require.Equal(t, []string{"service_name"}, labelNames(families["svc_requests"]))
+	})
+	t.Run("Options", func(t *testing.T) {
+		t.Setenv("OTEL_EXPORTER_PROMETHEUS_LEGACY_COLLECTORS", "false")
+		families := gatherPrometheus(t, prometheus.NewRegistry(),
+			autometer.WithPrometheusLegacyCollectors(true),
+			autometer.WithPrometheusOptions(otelprometheus.WithNamespace("opt")),
+		)
+		require.Contains(t, families, "opt_requests_seconds_total")
+		require.Contains(t, families, "go_goroutines")
+	})
+	t.Run("Config", func(t *testing.T) {
+		c, err := otelconfig.Parse([]byte(`file_format: "0.3"
+meter_provider:
+  readers:
+    - pull:
+        exporter:
+          prometheus:
+            translation_strategy: NoTranslation
+            without_target_info: true
+            legacy_collectors: false
+            with_resource_constant_labels:
+              included: [service.name]
+`))
+		require.NoError(t, err)
+		families := gatherPrometheus(t, prometheus.NewRegistry(), autometer.WithConfig(c))
+		require.Len(t, families, 1)
+		require.Contains(t, families, "requests")
+		require.Contains(t, labelNames(families["requests"]), "service.name")
+	})
+	t.Run("DefaultRegistry", func(t *testing.T) {
+		ctx := context.Background()
+		var reg *prometheus.Registry
+		_, stop, err := autometer.NewMeterProvider(ctx,
+			autometer.WithOnPrometheusRegistry(func(r *prometheus.Registry) { reg = r }),
+		)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, stop(ctx)) }()
+		require.NotNil(t, reg)
+
+		// Pedantic registry fails on collected metrics that were not described.
+		require.NoError(t, reg.Register(undescribedCollector{}))
+		_, err = reg.Gather()
+		require.NoError(t, err)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		for _, tt := range []struct {
+			name, value string
+		}{
+			{"OTEL_EXPORTER_PROMETHEUS_TRANSLATION_STRATEGY", "Escaping"},
+			{"OTEL_EXPORTER_PROMETHEUS_WITHOUT_SCOPE_INFO", "maybe"},
+			{"OTEL_EXPORTER_PROMETHEUS_LEGACY_COLLECTORS", "maybe"},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Setenv(tt.name, tt.value)
+				_, _, err := autometer.NewMeterProvider(context.Background(),
+					autometer.WithPrometheusRegisterer(prometheus.NewRegistry()),
+				)
+				require.ErrorContains(t, err, tt.name)
+			})
+		}
+	})
+}
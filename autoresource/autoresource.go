@@ -0,0 +1,47 @@
+// Package autoresource provides OpenTelemetry resource detectors for
+// containers and Kubernetes.
+//
+// Detectors read files from [fs.FS] that represents root filesystem,
+// so they can be tested against fake procfs. Detected resources are schemaless
+// to be merged with resources of any schema version.
+package autoresource
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// EnvFile is environment variable with path to file with resource attributes.
+const EnvFile = "OTEL_RESOURCE_ATTRIBUTES_FILE"
+
+// Root returns root filesystem.
+func Root() fs.FS {
+	return os.DirFS("/")
+}
+
+// rel returns path relative to filesystem root.
+func rel(name string) string {
+	return strings.TrimPrefix(name, "/")
+}
+
+// Detectors returns container and Kubernetes detectors for fsys and
+// file detector if OTEL_RESOURCE_ATTRIBUTES_FILE is set.
+//
+// Relative OTEL_RESOURCE_ATTRIBUTES_FILE is resolved against working directory.
+func Detectors(fsys fs.FS) []resource.Detector {
+	detectors := []resource.Detector{
+		Container(fsys),
+		Kubernetes(fsys),
+	}
+	if name := os.Getenv(EnvFile); name != "" {
+		if abs, err := filepath.Abs(name); err == nil {
+			name = filepath.ToSlash(abs)
+		}
+		detectors = append(detectors, File(fsys, name))
+	}
+	return detectors
+}
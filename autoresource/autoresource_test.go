@@ -0,0 +1,160 @@
+package autoresource
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+const testID = "3f4a8e5c2b1d9f7e6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f"
+
+func detect(t *testing.T, d resource.Detector) []attribute.KeyValue {
+	t.Helper()
+	res, err := d.Detect(context.Background())
+	require.NoError(t, err)
+	return res.Attributes()
+}
+
+func TestContainer(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		fsys  fstest.MapFS
+		empty bool
+	}{
+		{
+			name: "Docker",
+			fsys: fstest.MapFS{
+				"proc/self/cgroup": {Data: []byte("12:memory:/docker/" + testID + "\n")},
+			},
+		},
+		{
+			name: "Containerd",
+			fsys: fstest.MapFS{
+				"proc/self/cgroup": {Data: []byte(
+					"0::/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod1.slice/cri-containerd-" + testID + ".scope\n",
+				)},
+			},
+		},
+		{
+			name: "Mountinfo",
+			fsys: fstest.MapFS{
+				"proc/self/cgroup": {Data: []byte("0::/\n")},
+				"proc/self/mountinfo": {Data: []byte(strings.Join([]string{
+					"600 500 0:50 / / rw - overlay overlay rw",
+					"601 600 8:1 /var/lib/docker/containers/" + testID + "/hostname /etc/hostname rw - ext4 /dev/sda1 rw",
+				}, "\n"))},
+			},
+		},
+		{
+			name: "Host",
+			fsys: fstest.MapFS{
+				"proc/self/cgroup":    {Data: []byte("0::/user.slice/user-1000.slice/session-1.scope\n")},
+				"proc/self/mountinfo": {Data: []byte("22 1 8:1 / / rw - ext4 /dev/sda1 rw\n")},
+			},
+			empty: true,
+		},
+		{
+			name:  "NoProc",
+			fsys:  fstest.MapFS{},
+			empty: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			attrs := detect(t, Container(tt.fsys))
+			if tt.empty {
+				require.Empty(t, attrs)
+				return
+			}
+			require.Equal(t, []attribute.KeyValue{attribute.String("container.id", testID)}, attrs)
+		})
+	}
+}
+
+func TestKubernetes(t *testing.T) {
+	t.Run("Env", func(t *testing.T) {
+		t.Setenv("K8S_POD_NAME", "api-0")
+		t.Setenv("K8S_POD_UID", "uid")
+		t.Setenv("K8S_NAMESPACE_NAME", "prod")
+		t.Setenv("K8S_NODE_NAME", "node-1")
+		t.Setenv("K8S_CONTAINER_NAME", "api")
+		require.ElementsMatch(t, []attribute.KeyValue{
+			attribute.String("k8s.pod.name", "api-0"),
+			attribute.String("k8s.pod.uid", "uid"),
+			attribute.String("k8s.namespace.name", "prod"),
+			attribute.String("k8s.node.name", "node-1"),
+			attribute.String("k8s.container.name", "api"),
+		}, detect(t, Kubernetes(fstest.MapFS{})))
+	})
+	t.Run("Volume", func(t *testing.T) {
+		for _, env := range []string{"K8S_POD_NAME", "K8S_POD_UID", "K8S_NAMESPACE_NAME", "K8S_NODE_NAME", "K8S_CONTAINER_NAME"} {
+			t.Setenv(env, "")
+		}
+		t.Setenv(EnvPodInfoDir, "/podinfo")
+		fsys := fstest.MapFS{
+			"podinfo/name":   {Data: []byte("api-1\n")},
+			"podinfo/uid":    {Data: []byte("uid-1")},
+			"podinfo/labels": {Data: []byte("app=\"api\"\ntier=\"backend\"\n")},
+			"var/run/secrets/kubernetes.io/serviceaccount/namespace": {Data: []byte("staging")},
+		}
+		require.ElementsMatch(t, []attribute.KeyValue{
+			attribute.String("k8s.pod.name", "api-1"),
+			attribute.String("k8s.pod.uid", "uid-1"),
+			attribute.String("k8s.namespace.name", "staging"),
+			attribute.String("k8s.pod.label.app", "api"),
+			attribute.String("k8s.pod.label.tier", "backend"),
+		}, detect(t, Kubernetes(fsys)))
+	})
+	t.Run("None", func(t *testing.T) {
+		for _, env := range []string{"K8S_POD_NAME", "K8S_POD_UID", "K8S_NAMESPACE_NAME", "K8S_NODE_NAME", "K8S_CONTAINER_NAME"} {
+			t.Setenv(env, "")
+		}
+		require.Empty(t, detect(t, Kubernetes(fstest.MapFS{})))
+	})
+}
+
+func TestFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"etc/otel/resource": {Data: []byte("# Deployment.\nteam=platform, region=eu%20west\n\ndeployment.environment=prod\n")},
+		"etc/otel/invalid":  {Data: []byte("team\n")},
+	}
+	require.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("team", "platform"),
+		attribute.String("region", "eu west"),
+		attribute.String("deployment.environment", "prod"),
+	}, detect(t, File(fsys, "/etc/otel/resource")))
+
+	_, err := File(fsys, "/etc/otel/invalid").Detect(context.Background())
+	require.Error(t, err)
+	_, err = File(fsys, "/etc/otel/missing").Detect(context.Background())
+	require.Error(t, err)
+}
+
+func TestDetectors(t *testing.T) {
+	t.Setenv(EnvFile, "")
+	require.Len(t, Detectors(fstest.MapFS{}), 2)
+	t.Setenv(EnvFile, "/etc/otel/resource")
+	require.Len(t, Detectors(fstest.MapFS{}), 3)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	fsys := fstest.MapFS{
+		rel(filepath.ToSlash(filepath.Join(wd, "attrs.txt"))): {Data: []byte("team=platform\n")},
+	}
+	for _, name := range []string{"attrs.txt", "./attrs.txt"} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(EnvFile, name)
+			detectors := Detectors(fsys)
+			require.Len(t, detectors, 3)
+			require.Equal(t, []attribute.KeyValue{
+				attribute.String("team", "platform"),
+			}, detect(t, detectors[2]))
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package autoresource
+
+import (
+	"context"
+	"io/fs"
+	"strings"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+type containerDetector struct {
+	fsys fs.FS
+}
+
+// Container returns detector of container.id from /proc/self/cgroup
+// and /proc/self/mountinfo in fsys.
+//
+// Empty resource is detected outside of container.
+func Container(fsys fs.FS) resource.Detector {
+	return containerDetector{fsys: fsys}
+}
+
+func (d containerDetector) Detect(context.Context) (*resource.Resource, error) {
+	id, ok := containerID(d.fsys)
+	if !ok {
+		return resource.Empty(), nil
+	}
+	return resource.NewSchemaless(semconv.ContainerID(id)), nil
+}
+
+// containerID finds container ID in cgroup paths, falling back to mount
+// points which are used if cgroup namespace hides container cgroup.
+func containerID(fsys fs.FS) (string, bool) {
+	if data, err := fs.ReadFile(fsys, "proc/self/cgroup"); err == nil {
+		for line := range strings.SplitSeq(string(data), "\n") {
+			// 12:memory:/kubepods/burstable/pod1/cri-containerd-<id>.scope
+			parts := strings.SplitN(line, ":", 3)
+			if len(parts) != 3 {
+				continue
+			}
+			p := parts[2]
+			if i := strings.LastIndexByte(p, '/'); i >= 0 {
+				p = p[i+1:]
+			}
+			p = strings.TrimSuffix(p, ".scope")
+			if i := strings.LastIndexAny(p, "-:"); i >= 0 {
+				// docker-<id>, crio-<id>, cri-containerd-<id>.
+				p = p[i+1:]
+			}
+			if isContainerID(p) {
+				return p, true
+			}
+		}
+	}
+	if data, err := fs.ReadFile(fsys, "proc/self/mountinfo"); err == nil {
+		for line := range strings.SplitSeq(string(data), "\n") {
+			// 1 2 0:1 /var/lib/docker/containers/<id>/hostname /etc/hostname rw - ext4 /dev/sda1 rw
+			fields := strings.Fields(line)
+			if len(fields) < 4 {
+				continue
+			}
+			segments := strings.Split(fields[3], "/")
+			for i, s := range segments[:len(segments)-1] {
+				if s != "containers" && s != "overlay-containers" {
+					continue
+				}
+				if id := segments[i+1]; isContainerID(id) {
+					return id, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// isContainerID reports whether s is 64 hex characters.
+func isContainerID(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9', c >= 'a' && c <= 'f':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package autoresource
+
+import (
+	"context"
+	"io/fs"
+	"net/url"
+	"strings"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+type fileDetector struct {
+	fsys fs.FS
+	name string
+}
+
+// File returns detector of attributes from file in fsys, e.g. mounted
+// ConfigMap.
+//
+// File has same format as OTEL_RESOURCE_ATTRIBUTES: key=value pairs,
+// separated by commas or new lines, with percent-encoded values.
+// Empty lines and lines starting with # are ignored.
+func File(fsys fs.FS, name string) resource.Detector {
+	return fileDetector{fsys: fsys, name: name}
+}
+
+func (d fileDetector) Detect(context.Context) (*resource.Resource, error) {
+	data, err := fs.ReadFile(d.fsys, rel(d.name))
+	if err != nil {
+		return nil, errors.Wrap(err, "read resource attributes")
+	}
+	attrs, err := parseAttributes(string(data))
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse %q", d.name)
+	}
+	return resource.NewSchemaless(attrs...), nil
+}
+
+func parseAttributes(s string) ([]attribute.KeyValue, error) {
+	var attrs []attribute.KeyValue
+	for line := range strings.SplitSeq(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for pair := range strings.SplitSeq(line, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			k, v, ok := strings.Cut(pair, "=")
+			k = strings.TrimSpace(k)
+			if !ok || k == "" {
+				return nil, errors.Errorf("invalid pair %q", pair)
+			}
+			value, err := url.PathUnescape(strings.TrimSpace(v))
+			if err != nil {
+				return nil, errors.Wrapf(err, "decode %q", k)
+			}
+			attrs = append(attrs, attribute.String(k, value))
+		}
+	}
+	return attrs, nil
+}
@@ -0,0 +1,98 @@
+package autoresource
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+const (
+	// EnvPodInfoDir is environment variable with path of downward API volume.
+	EnvPodInfoDir = "K8S_PODINFO_DIR"
+
+	defaultPodInfoDir       = "/etc/podinfo"
+	serviceAccountNamespace = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+type kubernetesDetector struct {
+	fsys fs.FS
+}
+
+// Kubernetes returns detector of pod, namespace, node and container
+// attributes.
+//
+// Attributes are read from environment variables that are set by downward API:
+//
+//	K8S_POD_NAME       (metadata.name)
+//	K8S_POD_UID        (metadata.uid)
+//	K8S_NAMESPACE_NAME (metadata.namespace)
+//	K8S_NODE_NAME      (spec.nodeName)
+//	K8S_CONTAINER_NAME
+//
+// Missing pod attributes are read from downward API volume mounted to
+// K8S_PODINFO_DIR (defaults to /etc/podinfo) in fsys, with "name", "uid",
+// "namespace" and "labels" files. Namespace falls back to service account namespace.
+func Kubernetes(fsys fs.FS) resource.Detector {
+	return kubernetesDetector{fsys: fsys}
+}
+
+func (d kubernetesDetector) Detect(context.Context) (*resource.Resource, error) {
+	dir := defaultPodInfoDir
+	if v := os.Getenv(EnvPodInfoDir); v != "" {
+		dir = v
+	}
+	dir = rel(dir)
+	lookup := func(env string, files ...string) string {
+		if v := os.Getenv(env); v != "" {
+			return v
+		}
+		for _, name := range files {
+			if data, err := fs.ReadFile(d.fsys, name); err == nil {
+				if v := strings.TrimSpace(string(data)); v != "" {
+					return v
+				}
+			}
+		}
+		return ""
+	}
+
+	var attrs []attribute.KeyValue
+	for _, a := range []struct {
+		value string
+		attr  func(string) attribute.KeyValue
+	}{
+		{lookup("K8S_POD_NAME", path.Join(dir, "name")), semconv.K8SPodName},
+		{lookup("K8S_POD_UID", path.Join(dir, "uid")), semconv.K8SPodUID},
+		{lookup("K8S_NAMESPACE_NAME", path.Join(dir, "namespace"), rel(serviceAccountNamespace)), semconv.K8SNamespaceName},
+		{lookup("K8S_NODE_NAME"), semconv.K8SNodeName},
+		{lookup("K8S_CONTAINER_NAME"), semconv.K8SContainerName},
+	} {
+		if a.value != "" {
+			attrs = append(attrs, a.attr(a.value))
+		}
+	}
+	if data, err := fs.ReadFile(d.fsys, path.Join(dir, "labels")); err == nil {
+		for line := range strings.SplitSeq(string(data), "\n") {
+			// app.kubernetes.io/name="api"
+			k, v, ok := strings.Cut(strings.TrimSpace(line), "=")
+			if !ok {
+				continue
+			}
+			if s, err := strconv.Unquote(v); err == nil {
+				v = s
+			}
+			attrs = append(attrs, semconv.K8SPodLabel(k, v))
+		}
+	}
+	if len(attrs) == 0 {
+		return resource.Empty(), nil
+	}
+	return resource.NewSchemaless(attrs...), nil
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"io"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/go-faster/errors"
@@ -17,6 +18,8 @@ import (
 	"go.opentelemetry.io/otel/trace/noop"
 	"go.uber.org/zap"
 
+	"github.com/go-faster/sdk/internal/otlpjson"
+	"github.com/go-faster/sdk/otelconfig"
 	"github.com/go-faster/sdk/zctx"
 )
 
@@ -26,6 +29,7 @@ const (
 
 	protoHTTP         = "http"
 	protoHTTPProtobuf = "http/protobuf"
+	protoHTTPJSON     = "http/json"
 	protoGRPC         = "grpc"
 	defaultProto      = protoGRPC
 )
@@ -64,17 +68,78 @@ func NewTracerProvider(ctx context.Context, options ...Option) (
 ) {
 	cfg := newConfig(options)
 	lg := zctx.From(ctx)
+	if cfg.file == nil {
+		if cfg.file, err = otelconfig.FromEnv(); err != nil {
+			return nil, nil, errors.Wrap(err, "load config")
+		}
+	}
+	if cfg.file != nil {
+		lg.Debug("Using declarative configuration")
+		return newFromConfig(ctx, cfg, cfg.file)
+	}
 	var traceOptions []sdktrace.TracerProviderOption
 	if cfg.res != nil {
 		traceOptions = append(traceOptions, sdktrace.WithResource(cfg.res))
 	}
-	ret := func(e sdktrace.SpanExporter) (trace.TracerProvider, func(ctx context.Context) error, error) {
-		traceOptions = append(traceOptions, sdktrace.WithBatcher(e))
-		provider := sdktrace.NewTracerProvider(traceOptions...)
-		return provider, provider.Shutdown, nil
+	for _, sp := range cfg.processors {
+		traceOptions = append(traceOptions, sdktrace.WithSpanProcessor(sp))
+	}
+	exporters := exporterNames(lg, getEnvOr("OTEL_TRACES_EXPORTER", expOTLP))
+	if len(exporters) == 0 {
+		if len(cfg.processors) > 0 {
+			lg.Debug("Using only span processors")
+			provider := sdktrace.NewTracerProvider(traceOptions...)
+			return provider, provider.Shutdown, nil
+		}
+		lg.Debug("Using no-op trace exporter")
+		return noop.NewTracerProvider(), nop, nil
+	}
+	var created []sdktrace.SpanExporter
+	for _, exporter := range exporters {
+		e, err := newExporter(ctx, cfg, exporter)
+		if err != nil {
+			// Releasing already created exporters.
+			for _, e := range created {
+				_ = e.Shutdown(ctx)
+			}
+			return nil, nil, err
+		}
+		created = append(created, e)
+		traceOptions = append(traceOptions, sdktrace.WithSpanProcessor(
+			cfg.stats.BatchSpanProcessor(exporter, e, 0),
+		))
+	}
+	provider := sdktrace.NewTracerProvider(traceOptions...)
+	return provider, provider.Shutdown, nil
+}
+
+// exporterNames returns exporter names from comma-separated list.
+//
+// Empty entries and duplicates are skipped. As per specification, "none"
+// is ignored if other exporters are set. Empty result means no exporters.
+func exporterNames(lg *zap.Logger, v string) []string {
+	var (
+		names []string
+		none  bool
+	)
+	for name := range strings.SplitSeq(v, ",") {
+		switch name = strings.TrimSpace(name); {
+		case name == "" || slices.Contains(names, name):
+		case name == expNone:
+			none = true
+		default:
+			names = append(names, name)
+		}
+	}
+	if none && len(names) > 0 {
+		lg.Warn("Ignoring traces exporter \"none\" mixed with other exporters", zap.Strings("exporters", names))
 	}
+	return names
+}
 
-	exporter := strings.TrimSpace(getEnvOr("OTEL_TRACES_EXPORTER", expOTLP))
+// newExporter creates span exporter by name.
+func newExporter(ctx context.Context, cfg config, exporter string) (sdktrace.SpanExporter, error) {
+	lg := zctx.From(ctx)
 	switch exporter {
 	case expOTLP:
 		proto := os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
@@ -86,20 +151,28 @@ func NewTracerProvider(ctx context.Context, options ...Option) (
 		}
 		lg.Debug("Using OTLP trace exporter", zap.String("protocol", proto))
 		switch proto {
-		case protoHTTP, protoHTTPProtobuf:
-			exp, err := otlptracehttp.New(ctx)
+		case protoHTTP, protoHTTPProtobuf, protoHTTPJSON:
+			var opts []otlptracehttp.Option
+			if proto == protoHTTPJSON {
+				client, err := otlpjson.ConfigFromEnv(otlpjson.Traces)
+				if err != nil {
+					return nil, errors.Wrap(err, "configure OTLP JSON client")
+				}
+				opts = append(opts, otlptracehttp.WithHTTPClient(otlpjson.NewClient(otlpjson.Traces, client)))
+			}
+			exp, err := otlptracehttp.New(ctx, opts...)
 			if err != nil {
-				return nil, nil, errors.Wrap(err, "create OTLP HTTP trace exporter")
+				return nil, errors.Wrap(err, "create OTLP HTTP trace exporter")
 			}
-			return ret(exp)
+			return exp, nil
 		case protoGRPC:
 			exp, err := otlptracegrpc.New(ctx)
 			if err != nil {
-				return nil, nil, errors.Wrap(err, "create OTLP gRPC trace exporter")
+				return nil, errors.Wrap(err, "create OTLP gRPC trace exporter")
 			}
-			return ret(exp)
+			return exp, nil
 		default:
-			return nil, nil, errors.Errorf("unsupported traces otlp protocol %q", proto)
+			return nil, errors.Errorf("unsupported traces otlp protocol %q", proto)
 		}
 	case writerStdout, writerStderr:
 		lg.Debug("Using stdout trace exporter", zap.String("writer", exporter))
@@ -109,12 +182,9 @@ func NewTracerProvider(ctx context.Context, options ...Option) (
 		}
 		exp, err := stdouttrace.New(stdouttrace.WithWriter(writer))
 		if err != nil {
-			return nil, nil, errors.Wrapf(err, "create %q trace exporter", exporter)
+			return nil, errors.Wrapf(err, "create %q trace exporter", exporter)
 		}
-		return ret(exp)
-	case expNone:
-		lg.Debug("Using no-op trace exporter")
-		return noop.NewTracerProvider(), nop, nil
+		return exp, nil
 	default:
 		lookup := cfg.lookup
 		if lookup == nil {
@@ -123,14 +193,14 @@ func NewTracerProvider(ctx context.Context, options ...Option) (
 		lg.Debug("Looking for traces exporter", zap.String("exporter", exporter))
 		exp, ok, err := lookup(ctx, exporter)
 		if err != nil {
-			return nil, nil, errors.Wrapf(err, "create %q", exporter)
+			return nil, errors.Wrapf(err, "create %q", exporter)
 		}
 		if !ok {
 			break
 		}
 
 		lg.Debug("Using user-defined traces exporter", zap.String("exporter", exporter))
-		return ret(exp)
+		return exp, nil
 	}
-	return nil, nil, errors.Errorf("unsupported OTEL_TRACES_EXPORTER %q", exporter)
+	return nil, errors.Errorf("unsupported OTEL_TRACES_EXPORTER %q", exporter)
 }
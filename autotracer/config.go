@@ -6,6 +6,9 @@ import (
 
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/go-faster/sdk/otelconfig"
+	"github.com/go-faster/sdk/otelstats"
 )
 
 // config contains configuration options for a MeterProvider.
@@ -13,6 +16,10 @@ type config struct {
 	res    *resource.Resource
 	writer io.Writer
 	lookup LookupExporter
+	file   *otelconfig.Config
+	stats  *otelstats.Stats
+
+	processors []sdktrace.SpanProcessor
 }
 
 // newConfig returns a config configured with options.
@@ -58,6 +65,19 @@ func WithWriter(out io.Writer) Option {
 	})
 }
 
+// WithSpanProcessor registers span processors alongside the exporter.
+//
+// Processors are registered even if exporter is "none", so spans are
+// recorded and can be inspected in-process, e.g. by [tracez.Processor].
+//
+// [tracez.Processor]: https://pkg.go.dev/github.com/go-faster/sdk/tracez#Processor
+func WithSpanProcessor(processors ...sdktrace.SpanProcessor) Option {
+	return optionFunc(func(conf config) config {
+		conf.processors = append(conf.processors, processors...)
+		return conf
+	})
+}
+
 // LookupExporter creates exporter by name.
 type LookupExporter func(ctx context.Context, name string) (sdktrace.SpanExporter, bool, error)
 
@@ -68,3 +88,24 @@ func WithLookupExporter(lookup LookupExporter) Option {
 		return conf
 	})
 }
+
+// WithConfig sets declarative configuration that is used instead of
+// environment variables.
+//
+// By default, configuration is loaded from file set by OTEL_CONFIG_FILE
+// environment variable, if any. Exporters that are not known are created
+// by [LookupExporter].
+func WithConfig(c *otelconfig.Config) Option {
+	return optionFunc(func(conf config) config {
+		conf.file = c
+		return conf
+	})
+}
+
+// WithStats sets statistics collector of exporters and batch processors.
+func WithStats(s *otelstats.Stats) Option {
+	return optionFunc(func(conf config) config {
+		conf.stats = s
+		return conf
+	})
+}
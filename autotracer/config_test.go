@@ -1,15 +1,24 @@
 package autotracer
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
 	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/go-faster/sdk/otelconfig"
 )
 
 func TestWithLookupExporter(t *testing.T) {
@@ -47,3 +56,153 @@ func TestWithLookupExporter(t *testing.T) {
 		})
 	}
 }
+
+func TestWithConfig(t *testing.T) {
+	ctx := context.Background()
+	c, err := otelconfig.Parse([]byte(`file_format: "0.3"
+resource:
+  attributes:
+    - name: service.name
+      value: test
+tracer_provider:
+  sampler:
+    always_on:
+  processors:
+    - simple:
+        exporter:
+          console: {}
+    - simple:
+        exporter:
+          custom: {}
+`))
+	require.NoError(t, err)
+
+	t.Run("Console", func(t *testing.T) {
+		var (
+			out    bytes.Buffer
+			custom = tracetest.NewInMemoryExporter()
+		)
+		provider, stop, err := NewTracerProvider(ctx,
+			WithConfig(c),
+			WithWriter(&out),
+			WithLookupExporter(func(ctx context.Context, name string) (trace.SpanExporter, bool, error) {
+				return custom, name == "custom", nil
+			}),
+		)
+		require.NoError(t, err)
+
+		_, span := provider.Tracer("test").Start(ctx, "span")
+		span.End()
+		require.Len(t, custom.GetSpans(), 1)
+		require.NoError(t, stop(ctx))
+
+		require.Contains(t, out.String(), `"Name":"span"`)
+		require.Contains(t, out.String(), `"Value":"test"`)
+	})
+	t.Run("UnknownExporter", func(t *testing.T) {
+		_, _, err := NewTracerProvider(ctx, WithConfig(c), WithWriter(io.Discard))
+		require.ErrorContains(t, err, `tracer_provider.processors[1].simple.exporter.custom (line 15:19): unsupported traces exporter "custom"`)
+	})
+	t.Run("Disabled", func(t *testing.T) {
+		c, err := otelconfig.Parse([]byte("file_format: '0.3'\ndisabled: true"))
+		require.NoError(t, err)
+		provider, _, err := NewTracerProvider(ctx, WithConfig(c))
+		require.NoError(t, err)
+		require.IsType(t, noop.TracerProvider{}, provider)
+	})
+}
+
+func TestWithSpanProcessor(t *testing.T) {
+	ctx := context.Background()
+	t.Run("None", func(t *testing.T) {
+		t.Setenv("OTEL_TRACES_EXPORTER", "none")
+		recorder := tracetest.NewSpanRecorder()
+		provider, stop, err := NewTracerProvider(ctx, WithSpanProcessor(recorder))
+		require.NoError(t, err)
+
+		_, span := provider.Tracer("test").Start(ctx, "span")
+		span.End()
+		require.Len(t, recorder.Ended(), 1)
+		require.NoError(t, stop(ctx))
+	})
+	t.Run("Config", func(t *testing.T) {
+		c, err := otelconfig.Parse([]byte("file_format: '0.3'"))
+		require.NoError(t, err)
+		recorder := tracetest.NewSpanRecorder()
+		provider, stop, err := NewTracerProvider(ctx, WithConfig(c), WithSpanProcessor(recorder))
+		require.NoError(t, err)
+
+		_, span := provider.Tracer("test").Start(ctx, "span")
+		span.End()
+		require.Len(t, recorder.Ended(), 1)
+		require.NoError(t, stop(ctx))
+	})
+}
+
+func TestMultipleExporters(t *testing.T) {
+	ctx := context.Background()
+	exporters := map[string]*tracetest.InMemoryExporter{
+		"first":  tracetest.NewInMemoryExporter(),
+		"second": tracetest.NewInMemoryExporter(),
+	}
+	lookup := WithLookupExporter(func(ctx context.Context, name string) (trace.SpanExporter, bool, error) {
+		e, ok := exporters[name]
+		if !ok {
+			return nil, false, nil
+		}
+		return e, true, nil
+	})
+	t.Run("Positive", func(t *testing.T) {
+		t.Setenv("OTEL_TRACES_EXPORTER", " first,second, none,first")
+		provider, stop, err := NewTracerProvider(ctx, lookup)
+		require.NoError(t, err)
+
+		_, span := provider.Tracer("test").Start(ctx, "span")
+		span.End()
+		require.NoError(t, provider.(*trace.TracerProvider).ForceFlush(ctx))
+		for name, e := range exporters {
+			require.Len(t, e.GetSpans(), 1, name)
+		}
+		require.NoError(t, stop(ctx))
+	})
+	t.Run("Unsupported", func(t *testing.T) {
+		t.Setenv("OTEL_TRACES_EXPORTER", "first,unknown")
+		_, _, err := NewTracerProvider(ctx, lookup)
+		require.ErrorContains(t, err, `unsupported OTEL_TRACES_EXPORTER "unknown"`)
+	})
+	t.Run("None", func(t *testing.T) {
+		t.Setenv("OTEL_TRACES_EXPORTER", "none, ")
+		provider, _, err := NewTracerProvider(ctx, lookup)
+		require.NoError(t, err)
+		require.IsType(t, noop.TracerProvider{}, provider)
+	})
+}
+
+func TestHTTPJSON(t *testing.T) {
+	ctx := context.Background()
+	received := make(chan ptrace.Traces, 1)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/traces", r.URL.Path)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		req := ptraceotlp.NewExportRequest()
+		require.NoError(t, req.UnmarshalJSON(data))
+		received <- req.Traces()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, "{}")
+	}))
+	t.Cleanup(s.Close)
+	t.Setenv("OTEL_TRACES_EXPORTER", "otlp")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "http/json")
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", s.URL)
+
+	provider, stop, err := NewTracerProvider(ctx)
+	require.NoError(t, err)
+	_, span := provider.Tracer("test").Start(ctx, "span")
+	span.End()
+	require.NoError(t, stop(ctx))
+
+	td := <-received
+	require.Equal(t, "span", td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
+}
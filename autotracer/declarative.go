@@ -0,0 +1,234 @@
+package autotracer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/zap"
+
+	"github.com/go-faster/sdk/internal/otlpjson"
+	"github.com/go-faster/sdk/otelconfig"
+	"github.com/go-faster/sdk/zctx"
+)
+
+// newFromConfig creates TracerProvider from declarative configuration.
+func newFromConfig(ctx context.Context, cfg config, c *otelconfig.Config) (
+	tracerProvider trace.TracerProvider,
+	tracerShutdown ShutdownFunc,
+	rerr error,
+) {
+	tp := c.TracerProvider
+	if c.Disabled || (tp == nil && len(cfg.processors) == 0) {
+		zctx.From(ctx).Debug("Tracer provider is not configured, using no-op")
+		return noop.NewTracerProvider(), nop, nil
+	}
+	if tp == nil {
+		tp = &otelconfig.TracerProvider{}
+	}
+	res, err := c.MergeResource(ctx, cfg.res)
+	if err != nil {
+		return nil, nil, err
+	}
+	var traceOptions []sdktrace.TracerProviderOption
+	if res != nil {
+		traceOptions = append(traceOptions, sdktrace.WithResource(res))
+	}
+	if s := tp.Sampler; s != nil {
+		traceOptions = append(traceOptions, sdktrace.WithSampler(newSampler(s)))
+	}
+
+	for _, sp := range cfg.processors {
+		traceOptions = append(traceOptions, sdktrace.WithSpanProcessor(sp))
+	}
+
+	var processors []sdktrace.SpanProcessor
+	defer func() {
+		if rerr == nil {
+			return
+		}
+		for _, p := range processors {
+			_ = p.Shutdown(ctx)
+		}
+	}()
+	for i, p := range tp.Processors {
+		sp, err := newSpanProcessor(ctx, cfg, c, fmt.Sprintf("tracer_provider.processors[%d]", i), p)
+		if err != nil {
+			return nil, nil, err
+		}
+		processors = append(processors, sp)
+		traceOptions = append(traceOptions, sdktrace.WithSpanProcessor(sp))
+	}
+	provider := sdktrace.NewTracerProvider(traceOptions...)
+	return provider, provider.Shutdown, nil
+}
+
+func newSampler(s *otelconfig.Sampler) sdktrace.Sampler {
+	switch {
+	case s.AlwaysOff != nil:
+		return sdktrace.NeverSample()
+	case s.TraceIDRatioBased != nil:
+		ratio := 1.0
+		if v := s.TraceIDRatioBased.Ratio; v != nil {
+			ratio = *v
+		}
+		return sdktrace.TraceIDRatioBased(ratio)
+	case s.ParentBased != nil:
+		pb := s.ParentBased
+		root := sdktrace.AlwaysSample()
+		if pb.Root != nil {
+			root = newSampler(pb.Root)
+		}
+		var opts []sdktrace.ParentBasedSamplerOption
+		if v := pb.RemoteParentSampled; v != nil {
+			opts = append(opts, sdktrace.WithRemoteParentSampled(newSampler(v)))
+		}
+		if v := pb.RemoteParentNotSampled; v != nil {
+			opts = append(opts, sdktrace.WithRemoteParentNotSampled(newSampler(v)))
+		}
+		if v := pb.LocalParentSampled; v != nil {
+			opts = append(opts, sdktrace.WithLocalParentSampled(newSampler(v)))
+		}
+		if v := pb.LocalParentNotSampled; v != nil {
+			opts = append(opts, sdktrace.WithLocalParentNotSampled(newSampler(v)))
+		}
+		return sdktrace.ParentBased(root, opts...)
+	default:
+		return sdktrace.AlwaysSample()
+	}
+}
+
+func newSpanProcessor(ctx context.Context, cfg config, c *otelconfig.Config, path string, p otelconfig.Processor) (sdktrace.SpanProcessor, error) {
+	if b := p.Batch; b != nil {
+		exp, err := newSpanExporter(ctx, cfg, c, path+".batch.exporter", b.Exporter)
+		if err != nil {
+			return nil, err
+		}
+		var opts []sdktrace.BatchSpanProcessorOption
+		if v := b.ScheduleDelay; v != nil {
+			opts = append(opts, sdktrace.WithBatchTimeout(v.Duration()))
+		}
+		if v := b.ExportTimeout; v != nil {
+			opts = append(opts, sdktrace.WithExportTimeout(v.Duration()))
+		}
+		var queueSize int
+		if v := b.MaxQueueSize; v != nil {
+			queueSize = *v
+			opts = append(opts, sdktrace.WithMaxQueueSize(*v))
+		}
+		if v := b.MaxExportBatchSize; v != nil {
+			opts = append(opts, sdktrace.WithMaxExportBatchSize(*v))
+		}
+		return cfg.stats.BatchSpanProcessor(b.Exporter.Name(), exp, queueSize, opts...), nil
+	}
+	exp, err := newSpanExporter(ctx, cfg, c, path+".simple.exporter", p.Simple.Exporter)
+	if err != nil {
+		return nil, err
+	}
+	return sdktrace.NewSimpleSpanProcessor(cfg.stats.SpanExporter(p.Simple.Exporter.Name(), exp)), nil
+}
+
+func newSpanExporter(ctx context.Context, cfg config, c *otelconfig.Config, path string, e otelconfig.Exporter) (sdktrace.SpanExporter, error) {
+	var (
+		lg   = zctx.From(ctx)
+		name = e.Name()
+	)
+	path += "." + name
+	if o, proto, ok := e.OTLPConfig(); ok {
+		lg.Debug("Using OTLP trace exporter", zap.String("protocol", proto))
+		switch proto {
+		case otelconfig.ProtocolHTTPProtobuf, otelconfig.ProtocolHTTPJSON:
+			var opts []otlptracehttp.Option
+			if v := o.Endpoint; strings.Contains(v, "://") {
+				opts = append(opts, otlptracehttp.WithEndpointURL(v))
+			} else if v != "" {
+				opts = append(opts, otlptracehttp.WithEndpoint(v))
+			}
+			if o.Insecure {
+				opts = append(opts, otlptracehttp.WithInsecure())
+			}
+			if h := o.HeaderMap(); len(h) > 0 {
+				opts = append(opts, otlptracehttp.WithHeaders(h))
+			}
+			if o.Compression == "gzip" {
+				opts = append(opts, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+			}
+			if v := o.Timeout; v != nil {
+				opts = append(opts, otlptracehttp.WithTimeout(v.Duration()))
+			}
+			if proto == otelconfig.ProtocolHTTPJSON {
+				client, err := otlpjson.ConfigFromEnv(otlpjson.Traces)
+				if err != nil {
+					return nil, c.Wrap(path, errors.Wrap(err, "configure OTLP JSON client"))
+				}
+				if v := o.Timeout; v != nil {
+					client.Timeout = v.Duration()
+				}
+				opts = append(opts, otlptracehttp.WithHTTPClient(otlpjson.NewClient(otlpjson.Traces, client)))
+			}
+			exp, err := otlptracehttp.New(ctx, opts...)
+			if err != nil {
+				return nil, c.Wrap(path, errors.Wrap(err, "create OTLP HTTP trace exporter"))
+			}
+			return exp, nil
+		case otelconfig.ProtocolGRPC:
+			var opts []otlptracegrpc.Option
+			if v := o.Endpoint; strings.Contains(v, "://") {
+				opts = append(opts, otlptracegrpc.WithEndpointURL(v))
+			} else if v != "" {
+				opts = append(opts, otlptracegrpc.WithEndpoint(v))
+			}
+			if o.Insecure {
+				opts = append(opts, otlptracegrpc.WithInsecure())
+			}
+			if h := o.HeaderMap(); len(h) > 0 {
+				opts = append(opts, otlptracegrpc.WithHeaders(h))
+			}
+			if o.Compression == "gzip" {
+				opts = append(opts, otlptracegrpc.WithCompressor("gzip"))
+			}
+			if v := o.Timeout; v != nil {
+				opts = append(opts, otlptracegrpc.WithTimeout(v.Duration()))
+			}
+			exp, err := otlptracegrpc.New(ctx, opts...)
+			if err != nil {
+				return nil, c.Wrap(path, errors.Wrap(err, "create OTLP gRPC trace exporter"))
+			}
+			return exp, nil
+		default:
+			return nil, c.Errorf(path, "unsupported traces otlp protocol %q", proto)
+		}
+	}
+	if e.Console != nil {
+		lg.Debug("Using console trace exporter")
+		writer := cfg.writer
+		if writer == nil {
+			writer = os.Stdout
+		}
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(writer))
+		if err != nil {
+			return nil, c.Wrap(path, errors.Wrap(err, "create console trace exporter"))
+		}
+		return exp, nil
+	}
+	if lookup := cfg.lookup; lookup != nil {
+		lg.Debug("Looking for traces exporter", zap.String("exporter", name))
+		exp, ok, err := lookup(ctx, name)
+		if err != nil {
+			return nil, c.Wrap(path, errors.Wrapf(err, "create %q", name))
+		}
+		if ok {
+			lg.Debug("Using user-defined traces exporter", zap.String("exporter", name))
+			return exp, nil
+		}
+	}
+	return nil, c.Errorf(path, "unsupported traces exporter %q", name)
+}
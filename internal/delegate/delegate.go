@@ -0,0 +1,25 @@
+// Package delegate implements OpenTelemetry providers that delegate to
+// underlying provider which can be replaced at runtime.
+//
+// Tracers, meters, loggers and instruments returned by providers stay valid
+// after replacement and start to use new underlying provider.
+package delegate
+
+import "go.opentelemetry.io/otel/attribute"
+
+// scopeKey identifies instrumentation scope.
+type scopeKey struct {
+	name      string
+	version   string
+	schemaURL string
+	attrs     attribute.Distinct
+}
+
+func newScopeKey(name, version, schemaURL string, attrs attribute.Set) scopeKey {
+	return scopeKey{
+		name:      name,
+		version:   version,
+		schemaURL: schemaURL,
+		attrs:     attrs.Equivalent(),
+	}
+}
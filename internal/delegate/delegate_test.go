@@ -0,0 +1,157 @@
+package delegate
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/metric"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracerProvider(t *testing.T) {
+	ctx := context.Background()
+	first := tracetest.NewSpanRecorder()
+	second := tracetest.NewSpanRecorder()
+
+	p := NewTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(first)))
+	tracer := p.Tracer("test")
+	require.Same(t, tracer, p.Tracer("test"))
+
+	_, span := tracer.Start(ctx, "first")
+	span.End()
+
+	old := p.Set(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(second)))
+	require.NoError(t, old.(*sdktrace.TracerProvider).Shutdown(ctx))
+
+	_, span = tracer.Start(ctx, "second")
+	span.End()
+
+	require.Len(t, first.Ended(), 1)
+	require.Equal(t, "first", first.Ended()[0].Name())
+	require.Len(t, second.Ended(), 1)
+	require.Equal(t, "second", second.Ended()[0].Name())
+}
+
+type recordExporter struct {
+	mux     sync.Mutex
+	records []string
+}
+
+func (e *recordExporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Body().AsString())
+	}
+	return nil
+}
+
+func (e *recordExporter) Shutdown(context.Context) error   { return nil }
+func (e *recordExporter) ForceFlush(context.Context) error { return nil }
+
+func TestLoggerProvider(t *testing.T) {
+	ctx := context.Background()
+	var first, second recordExporter
+	newProvider := func(e *recordExporter) *sdklog.LoggerProvider {
+		return sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(e)))
+	}
+	emit := func(l log.Logger, body string) {
+		var r log.Record
+		r.SetBody(log.StringValue(body))
+		l.Emit(ctx, r)
+	}
+
+	p := NewLoggerProvider(newProvider(&first))
+	logger := p.Logger("test")
+	require.Same(t, logger, p.Logger("test"))
+	require.True(t, logger.Enabled(ctx, log.EnabledParameters{}))
+	emit(logger, "first")
+
+	p.Set(newProvider(&second))
+	emit(logger, "second")
+
+	require.Equal(t, []string{"first"}, first.records)
+	require.Equal(t, []string{"second"}, second.records)
+}
+
+func collect(t *testing.T, r sdkmetric.Reader) map[string]int64 {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, r.Collect(context.Background(), &rm))
+	out := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					out[m.Name] += dp.Value
+				}
+			case metricdata.Gauge[int64]:
+				for _, dp := range data.DataPoints {
+					out[m.Name] += dp.Value
+				}
+			}
+		}
+	}
+	return out
+}
+
+func TestMeterProvider(t *testing.T) {
+	ctx := context.Background()
+	first := sdkmetric.NewManualReader()
+	second := sdkmetric.NewManualReader()
+
+	p := NewMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(first)))
+	meter := p.Meter("test")
+	require.Same(t, meter, p.Meter("test"))
+
+	counter, err := meter.Int64Counter("counter")
+	require.NoError(t, err)
+	same, err := meter.Int64Counter("counter")
+	require.NoError(t, err)
+	require.True(t, counter.Enabled(ctx))
+
+	_, err = meter.Int64ObservableGauge("gauge", metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+		o.Observe(10)
+		return nil
+	}))
+	require.NoError(t, err)
+
+	registered, err := meter.Int64ObservableCounter("registered")
+	require.NoError(t, err)
+	reg, err := meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(registered, 5)
+		return nil
+	}, registered)
+	require.NoError(t, err)
+
+	counter.Add(ctx, 1)
+	same.Add(ctx, 1)
+	require.Equal(t, map[string]int64{
+		"counter":    2,
+		"gauge":      10,
+		"registered": 5,
+	}, collect(t, first))
+
+	old, err := p.Set(sdkmetric.NewMeterProvider(sdkmetric.WithReader(second)))
+	require.NoError(t, err)
+	require.NoError(t, old.(*sdkmetric.MeterProvider).Shutdown(ctx))
+
+	counter.Add(ctx, 3)
+	require.Equal(t, map[string]int64{
+		"counter":    3,
+		"gauge":      10,
+		"registered": 5,
+	}, collect(t, second))
+
+	require.NoError(t, reg.Unregister())
+	require.NoError(t, reg.Unregister())
+	require.NotContains(t, collect(t, second), "registered")
+}
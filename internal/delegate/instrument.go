@@ -0,0 +1,190 @@
+package delegate
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/embedded"
+)
+
+// instrument holds instrument T of current delegate.
+type instrument[T any] struct {
+	create   func(m metric.Meter) (T, error)
+	delegate atomic.Pointer[T]
+}
+
+// setDelegate re-creates instrument, keeping previous one if delegate
+// did not return any.
+func (i *instrument[T]) setDelegate(m metric.Meter) error {
+	v, err := i.create(m)
+	if any(v) != nil {
+		i.delegate.Store(&v)
+	}
+	return err
+}
+
+func (i *instrument[T]) load() T {
+	return *i.delegate.Load()
+}
+
+// unwrapper is implemented by observable instruments to get instrument
+// of current delegate.
+type unwrapper interface {
+	unwrap() metric.Observable
+}
+
+type int64Counter struct {
+	embedded.Int64Counter
+	*instrument[metric.Int64Counter]
+}
+
+func (i *int64Counter) Add(ctx context.Context, incr int64, options ...metric.AddOption) {
+	i.load().Add(ctx, incr, options...)
+}
+
+func (i *int64Counter) Enabled(ctx context.Context) bool {
+	return i.load().Enabled(ctx)
+}
+
+type int64UpDownCounter struct {
+	embedded.Int64UpDownCounter
+	*instrument[metric.Int64UpDownCounter]
+}
+
+func (i *int64UpDownCounter) Add(ctx context.Context, incr int64, options ...metric.AddOption) {
+	i.load().Add(ctx, incr, options...)
+}
+
+func (i *int64UpDownCounter) Enabled(ctx context.Context) bool {
+	return i.load().Enabled(ctx)
+}
+
+type int64Histogram struct {
+	embedded.Int64Histogram
+	*instrument[metric.Int64Histogram]
+}
+
+func (i *int64Histogram) Record(ctx context.Context, incr int64, options ...metric.RecordOption) {
+	i.load().Record(ctx, incr, options...)
+}
+
+func (i *int64Histogram) Enabled(ctx context.Context) bool {
+	return i.load().Enabled(ctx)
+}
+
+type int64Gauge struct {
+	embedded.Int64Gauge
+	*instrument[metric.Int64Gauge]
+}
+
+func (i *int64Gauge) Record(ctx context.Context, value int64, options ...metric.RecordOption) {
+	i.load().Record(ctx, value, options...)
+}
+
+func (i *int64Gauge) Enabled(ctx context.Context) bool {
+	return i.load().Enabled(ctx)
+}
+
+type float64Counter struct {
+	embedded.Float64Counter
+	*instrument[metric.Float64Counter]
+}
+
+func (i *float64Counter) Add(ctx context.Context, incr float64, options ...metric.AddOption) {
+	i.load().Add(ctx, incr, options...)
+}
+
+func (i *float64Counter) Enabled(ctx context.Context) bool {
+	return i.load().Enabled(ctx)
+}
+
+type float64UpDownCounter struct {
+	embedded.Float64UpDownCounter
+	*instrument[metric.Float64UpDownCounter]
+}
+
+func (i *float64UpDownCounter) Add(ctx context.Context, incr float64, options ...metric.AddOption) {
+	i.load().Add(ctx, incr, options...)
+}
+
+func (i *float64UpDownCounter) Enabled(ctx context.Context) bool {
+	return i.load().Enabled(ctx)
+}
+
+type float64Histogram struct {
+	embedded.Float64Histogram
+	*instrument[metric.Float64Histogram]
+}
+
+func (i *float64Histogram) Record(ctx context.Context, incr float64, options ...metric.RecordOption) {
+	i.load().Record(ctx, incr, options...)
+}
+
+func (i *float64Histogram) Enabled(ctx context.Context) bool {
+	return i.load().Enabled(ctx)
+}
+
+type float64Gauge struct {
+	embedded.Float64Gauge
+	*instrument[metric.Float64Gauge]
+}
+
+func (i *float64Gauge) Record(ctx context.Context, value float64, options ...metric.RecordOption) {
+	i.load().Record(ctx, value, options...)
+}
+
+func (i *float64Gauge) Enabled(ctx context.Context) bool {
+	return i.load().Enabled(ctx)
+}
+
+// Observable instruments embed observable interface to implement
+// unexported methods, it is never called.
+
+type int64ObservableCounter struct {
+	metric.Int64Observable
+	embedded.Int64ObservableCounter
+	*instrument[metric.Int64ObservableCounter]
+}
+
+func (i *int64ObservableCounter) unwrap() metric.Observable { return i.load() }
+
+type int64ObservableUpDownCounter struct {
+	metric.Int64Observable
+	embedded.Int64ObservableUpDownCounter
+	*instrument[metric.Int64ObservableUpDownCounter]
+}
+
+func (i *int64ObservableUpDownCounter) unwrap() metric.Observable { return i.load() }
+
+type int64ObservableGauge struct {
+	metric.Int64Observable
+	embedded.Int64ObservableGauge
+	*instrument[metric.Int64ObservableGauge]
+}
+
+func (i *int64ObservableGauge) unwrap() metric.Observable { return i.load() }
+
+type float64ObservableCounter struct {
+	metric.Float64Observable
+	embedded.Float64ObservableCounter
+	*instrument[metric.Float64ObservableCounter]
+}
+
+func (i *float64ObservableCounter) unwrap() metric.Observable { return i.load() }
+
+type float64ObservableUpDownCounter struct {
+	metric.Float64Observable
+	embedded.Float64ObservableUpDownCounter
+	*instrument[metric.Float64ObservableUpDownCounter]
+}
+
+func (i *float64ObservableUpDownCounter) unwrap() metric.Observable { return i.load() }
+
+type float64ObservableGauge struct {
+	metric.Float64Observable
+	embedded.Float64ObservableGauge
+	*instrument[metric.Float64ObservableGauge]
+}
+
+func (i *float64ObservableGauge) unwrap() metric.Observable { return i.load() }
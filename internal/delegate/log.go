@@ -0,0 +1,80 @@
+package delegate
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/embedded"
+)
+
+// LoggerProvider is [log.LoggerProvider] with replaceable delegate.
+type LoggerProvider struct {
+	embedded.LoggerProvider
+
+	mux      sync.Mutex
+	delegate log.LoggerProvider
+	loggers  map[scopeKey]*logger
+}
+
+var _ log.LoggerProvider = (*LoggerProvider)(nil)
+
+// NewLoggerProvider returns new LoggerProvider that delegates to p.
+func NewLoggerProvider(p log.LoggerProvider) *LoggerProvider {
+	return &LoggerProvider{
+		delegate: p,
+		loggers:  map[scopeKey]*logger{},
+	}
+}
+
+// Logger implements [log.LoggerProvider].
+func (p *LoggerProvider) Logger(name string, opts ...log.LoggerOption) log.Logger {
+	cfg := log.NewLoggerConfig(opts...)
+	key := newScopeKey(name, cfg.InstrumentationVersion(), cfg.SchemaURL(), cfg.InstrumentationAttributes())
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if l, ok := p.loggers[key]; ok {
+		return l
+	}
+	l := &logger{name: name, opts: opts}
+	l.setDelegate(p.delegate)
+	p.loggers[key] = l
+	return l
+}
+
+// Set replaces underlying provider, returning previous one.
+func (p *LoggerProvider) Set(delegate log.LoggerProvider) log.LoggerProvider {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	old := p.delegate
+	p.delegate = delegate
+	for _, l := range p.loggers {
+		l.setDelegate(delegate)
+	}
+	return old
+}
+
+type logger struct {
+	embedded.Logger
+
+	name     string
+	opts     []log.LoggerOption
+	delegate atomic.Pointer[log.Logger]
+}
+
+func (l *logger) setDelegate(p log.LoggerProvider) {
+	d := p.Logger(l.name, l.opts...)
+	l.delegate.Store(&d)
+}
+
+// Emit implements [log.Logger].
+func (l *logger) Emit(ctx context.Context, record log.Record) {
+	(*l.delegate.Load()).Emit(ctx, record)
+}
+
+// Enabled implements [log.Logger].
+func (l *logger) Enabled(ctx context.Context, param log.EnabledParameters) bool {
+	return (*l.delegate.Load()).Enabled(ctx, param)
+}
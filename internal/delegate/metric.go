@@ -0,0 +1,414 @@
+package delegate
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/embedded"
+)
+
+// MeterProvider is [metric.MeterProvider] with replaceable delegate.
+//
+// Instruments and callback registrations are re-created on
+// replacement.
+type MeterProvider struct {
+	embedded.MeterProvider
+
+	mux      sync.Mutex
+	delegate metric.MeterProvider
+	meters   map[scopeKey]*meter
+}
+
+var _ metric.MeterProvider = (*MeterProvider)(nil)
+
+// NewMeterProvider returns new MeterProvider that delegates to p.
+func NewMeterProvider(p metric.MeterProvider) *MeterProvider {
+	return &MeterProvider{
+		delegate: p,
+		meters:   map[scopeKey]*meter{},
+	}
+}
+
+// Meter implements [metric.MeterProvider].
+func (p *MeterProvider) Meter(name string, opts ...metric.MeterOption) metric.Meter {
+	cfg := metric.NewMeterConfig(opts...)
+	key := newScopeKey(name, cfg.InstrumentationVersion(), cfg.SchemaURL(), cfg.InstrumentationAttributes())
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if m, ok := p.meters[key]; ok {
+		return m
+	}
+	m := &meter{
+		name:          name,
+		opts:          opts,
+		delegate:      p.delegate.Meter(name, opts...),
+		cache:         map[instID]delegated{},
+		registrations: map[*registration]struct{}{},
+	}
+	p.meters[key] = m
+	return m
+}
+
+// Set replaces underlying provider, returning previous one.
+//
+// Provider is replaced even if some instruments or callbacks failed
+// to be re-created, returned error reports such failures.
+func (p *MeterProvider) Set(delegate metric.MeterProvider) (metric.MeterProvider, error) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	old := p.delegate
+	p.delegate = delegate
+	var errs []error
+	for _, m := range p.meters {
+		if err := m.setDelegate(delegate); err != nil {
+			errs = append(errs, errors.Wrapf(err, "meter %q", m.name))
+		}
+	}
+	return old, errors.Join(errs...)
+}
+
+// delegated is an entity that is re-created on delegate replacement.
+type delegated interface {
+	setDelegate(m metric.Meter) error
+}
+
+// instID identifies synchronous instrument.
+type instID struct {
+	name        string
+	kind        reflect.Type
+	description string
+	unit        string
+}
+
+type meter struct {
+	embedded.Meter
+
+	name string
+	opts []metric.MeterOption
+
+	mux           sync.Mutex
+	delegate      metric.Meter
+	instruments   []delegated
+	cache         map[instID]delegated
+	registrations map[*registration]struct{}
+}
+
+var _ metric.Meter = (*meter)(nil)
+
+func (m *meter) setDelegate(p metric.MeterProvider) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.delegate = p.Meter(m.name, m.opts...)
+
+	var errs []error
+	for _, i := range m.instruments {
+		if err := i.setDelegate(m.delegate); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	// Registrations reference instruments, so they are re-created last.
+	for r := range m.registrations {
+		if err := r.setDelegate(m.delegate); err != nil {
+			errs = append(errs, errors.Wrap(err, "register callback"))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// newInstrument creates instrument using create function.
+//
+// If id is not nil, instrument is cached and returned on subsequent calls.
+// Returns nil instrument if delegate failed to create one.
+func newInstrument[T any](m *meter, id *instID, create func(m metric.Meter) (T, error)) (*instrument[T], error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if id != nil {
+		if i, ok := m.cache[*id]; ok {
+			return i.(*instrument[T]), nil
+		}
+	}
+	i := &instrument[T]{create: create}
+	err := i.setDelegate(m.delegate)
+	if i.delegate.Load() == nil {
+		return nil, err
+	}
+	m.instruments = append(m.instruments, i)
+	if id != nil {
+		m.cache[*id] = i
+	}
+	return i, err
+}
+
+func (m *meter) Int64Counter(name string, options ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	cfg := metric.NewInt64CounterConfig(options...)
+	i, err := newInstrument(m, &instID{
+		name:        name,
+		kind:        reflect.TypeFor[metric.Int64Counter](),
+		description: cfg.Description(),
+		unit:        cfg.Unit(),
+	}, func(d metric.Meter) (metric.Int64Counter, error) {
+		return d.Int64Counter(name, options...)
+	})
+	if i == nil {
+		return nil, err
+	}
+	return &int64Counter{instrument: i}, err
+}
+
+func (m *meter) Int64UpDownCounter(name string, options ...metric.Int64UpDownCounterOption) (metric.Int64UpDownCounter, error) {
+	cfg := metric.NewInt64UpDownCounterConfig(options...)
+	i, err := newInstrument(m, &instID{
+		name:        name,
+		kind:        reflect.TypeFor[metric.Int64UpDownCounter](),
+		description: cfg.Description(),
+		unit:        cfg.Unit(),
+	}, func(d metric.Meter) (metric.Int64UpDownCounter, error) {
+		return d.Int64UpDownCounter(name, options...)
+	})
+	if i == nil {
+		return nil, err
+	}
+	return &int64UpDownCounter{instrument: i}, err
+}
+
+func (m *meter) Int64Histogram(name string, options ...metric.Int64HistogramOption) (metric.Int64Histogram, error) {
+	cfg := metric.NewInt64HistogramConfig(options...)
+	i, err := newInstrument(m, &instID{
+		name:        name,
+		kind:        reflect.TypeFor[metric.Int64Histogram](),
+		description: cfg.Description(),
+		unit:        cfg.Unit(),
+	}, func(d metric.Meter) (metric.Int64Histogram, error) {
+		return d.Int64Histogram(name, options...)
+	})
+	if i == nil {
+		return nil, err
+	}
+	return &int64Histogram{instrument: i}, err
+}
+
+func (m *meter) Int64Gauge(name string, options ...metric.Int64GaugeOption) (metric.Int64Gauge, error) {
+	cfg := metric.NewInt64GaugeConfig(options...)
+	i, err := newInstrument(m, &instID{
+		name:        name,
+		kind:        reflect.TypeFor[metric.Int64Gauge](),
+		description: cfg.Description(),
+		unit:        cfg.Unit(),
+	}, func(d metric.Meter) (metric.Int64Gauge, error) {
+		return d.Int64Gauge(name, options...)
+	})
+	if i == nil {
+		return nil, err
+	}
+	return &int64Gauge{instrument: i}, err
+}
+
+func (m *meter) Float64Counter(name string, options ...metric.Float64CounterOption) (metric.Float64Counter, error) {
+	cfg := metric.NewFloat64CounterConfig(options...)
+	i, err := newInstrument(m, &instID{
+		name:        name,
+		kind:        reflect.TypeFor[metric.Float64Counter](),
+		description: cfg.Description(),
+		unit:        cfg.Unit(),
+	}, func(d metric.Meter) (metric.Float64Counter, error) {
+		return d.Float64Counter(name, options...)
+	})
+	if i == nil {
+		return nil, err
+	}
+	return &float64Counter{instrument: i}, err
+}
+
+func (m *meter) Float64UpDownCounter(name string, options ...metric.Float64UpDownCounterOption) (metric.Float64UpDownCounter, error) {
+	cfg := metric.NewFloat64UpDownCounterConfig(options...)
+	i, err := newInstrument(m, &instID{
+		name:        name,
+		kind:        reflect.TypeFor[metric.Float64UpDownCounter](),
+		description: cfg.Description(),
+		unit:        cfg.Unit(),
+	}, func(d metric.Meter) (metric.Float64UpDownCounter, error) {
+		return d.Float64UpDownCounter(name, options...)
+	})
+	if i == nil {
+		return nil, err
+	}
+	return &float64UpDownCounter{instrument: i}, err
+}
+
+func (m *meter) Float64Histogram(name string, options ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	cfg := metric.NewFloat64HistogramConfig(options...)
+	i, err := newInstrument(m, &instID{
+		name:        name,
+		kind:        reflect.TypeFor[metric.Float64Histogram](),
+		description: cfg.Description(),
+		unit:        cfg.Unit(),
+	}, func(d metric.Meter) (metric.Float64Histogram, error) {
+		return d.Float64Histogram(name, options...)
+	})
+	if i == nil {
+		return nil, err
+	}
+	return &float64Histogram{instrument: i}, err
+}
+
+func (m *meter) Float64Gauge(name string, options ...metric.Float64GaugeOption) (metric.Float64Gauge, error) {
+	cfg := metric.NewFloat64GaugeConfig(options...)
+	i, err := newInstrument(m, &instID{
+		name:        name,
+		kind:        reflect.TypeFor[metric.Float64Gauge](),
+		description: cfg.Description(),
+		unit:        cfg.Unit(),
+	}, func(d metric.Meter) (metric.Float64Gauge, error) {
+		return d.Float64Gauge(name, options...)
+	})
+	if i == nil {
+		return nil, err
+	}
+	return &float64Gauge{instrument: i}, err
+}
+
+// Observable instruments are not cached, because each call can add
+// callbacks.
+
+func (m *meter) Int64ObservableCounter(name string, options ...metric.Int64ObservableCounterOption) (metric.Int64ObservableCounter, error) {
+	i, err := newInstrument(m, nil, func(d metric.Meter) (metric.Int64ObservableCounter, error) {
+		return d.Int64ObservableCounter(name, options...)
+	})
+	if i == nil {
+		return nil, err
+	}
+	return &int64ObservableCounter{instrument: i}, err
+}
+
+func (m *meter) Int64ObservableUpDownCounter(name string, options ...metric.Int64ObservableUpDownCounterOption) (metric.Int64ObservableUpDownCounter, error) {
+	i, err := newInstrument(m, nil, func(d metric.Meter) (metric.Int64ObservableUpDownCounter, error) {
+		return d.Int64ObservableUpDownCounter(name, options...)
+	})
+	if i == nil {
+		return nil, err
+	}
+	return &int64ObservableUpDownCounter{instrument: i}, err
+}
+
+func (m *meter) Int64ObservableGauge(name string, options ...metric.Int64ObservableGaugeOption) (metric.Int64ObservableGauge, error) {
+	i, err := newInstrument(m, nil, func(d metric.Meter) (metric.Int64ObservableGauge, error) {
+		return d.Int64ObservableGauge(name, options...)
+	})
+	if i == nil {
+		return nil, err
+	}
+	return &int64ObservableGauge{instrument: i}, err
+}
+
+func (m *meter) Float64ObservableCounter(name string, options ...metric.Float64ObservableCounterOption) (metric.Float64ObservableCounter, error) {
+	i, err := newInstrument(m, nil, func(d metric.Meter) (metric.Float64ObservableCounter, error) {
+		return d.Float64ObservableCounter(name, options...)
+	})
+	if i == nil {
+		return nil, err
+	}
+	return &float64ObservableCounter{instrument: i}, err
+}
+
+func (m *meter) Float64ObservableUpDownCounter(name string, options ...metric.Float64ObservableUpDownCounterOption) (metric.Float64ObservableUpDownCounter, error) {
+	i, err := newInstrument(m, nil, func(d metric.Meter) (metric.Float64ObservableUpDownCounter, error) {
+		return d.Float64ObservableUpDownCounter(name, options...)
+	})
+	if i == nil {
+		return nil, err
+	}
+	return &float64ObservableUpDownCounter{instrument: i}, err
+}
+
+func (m *meter) Float64ObservableGauge(name string, options ...metric.Float64ObservableGaugeOption) (metric.Float64ObservableGauge, error) {
+	i, err := newInstrument(m, nil, func(d metric.Meter) (metric.Float64ObservableGauge, error) {
+		return d.Float64ObservableGauge(name, options...)
+	})
+	if i == nil {
+		return nil, err
+	}
+	return &float64ObservableGauge{instrument: i}, err
+}
+
+func (m *meter) RegisterCallback(f metric.Callback, instruments ...metric.Observable) (metric.Registration, error) {
+	r := &registration{
+		meter:       m,
+		f:           f,
+		instruments: instruments,
+	}
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if err := r.setDelegate(m.delegate); err != nil {
+		return nil, err
+	}
+	m.registrations[r] = struct{}{}
+	return r, nil
+}
+
+type registration struct {
+	embedded.Registration
+
+	meter       *meter
+	f           metric.Callback
+	instruments []metric.Observable
+	delegate    metric.Registration // guarded by meter.mux
+}
+
+func (r *registration) setDelegate(m metric.Meter) error {
+	instruments := make([]metric.Observable, 0, len(r.instruments))
+	for _, i := range r.instruments {
+		if u, ok := i.(unwrapper); ok {
+			i = u.unwrap()
+		}
+		instruments = append(instruments, i)
+	}
+	f := r.f
+	delegate, err := m.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		return f(ctx, observer{delegate: o})
+	}, instruments...)
+	if err != nil {
+		return err
+	}
+	r.delegate = delegate
+	return nil
+}
+
+// Unregister implements [metric.Registration].
+func (r *registration) Unregister() error {
+	r.meter.mux.Lock()
+	defer r.meter.mux.Unlock()
+	if _, ok := r.meter.registrations[r]; !ok {
+		return nil
+	}
+	delete(r.meter.registrations, r)
+	return r.delegate.Unregister()
+}
+
+// observer unwraps delegating instruments before passing them to delegate.
+type observer struct {
+	embedded.Observer
+
+	delegate metric.Observer
+}
+
+func (o observer) ObserveFloat64(inst metric.Float64Observable, value float64, opts ...metric.ObserveOption) {
+	if u, ok := inst.(unwrapper); ok {
+		if d, ok := u.unwrap().(metric.Float64Observable); ok {
+			inst = d
+		}
+	}
+	o.delegate.ObserveFloat64(inst, value, opts...)
+}
+
+func (o observer) ObserveInt64(inst metric.Int64Observable, value int64, opts ...metric.ObserveOption) {
+	if u, ok := inst.(unwrapper); ok {
+		if d, ok := u.unwrap().(metric.Int64Observable); ok {
+			inst = d
+		}
+	}
+	o.delegate.ObserveInt64(inst, value, opts...)
+}
@@ -0,0 +1,75 @@
+package delegate
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+)
+
+// TracerProvider is [trace.TracerProvider] with replaceable delegate.
+type TracerProvider struct {
+	embedded.TracerProvider
+
+	mux      sync.Mutex
+	delegate trace.TracerProvider
+	tracers  map[scopeKey]*tracer
+}
+
+var _ trace.TracerProvider = (*TracerProvider)(nil)
+
+// NewTracerProvider returns new TracerProvider that delegates to p.
+func NewTracerProvider(p trace.TracerProvider) *TracerProvider {
+	return &TracerProvider{
+		delegate: p,
+		tracers:  map[scopeKey]*tracer{},
+	}
+}
+
+// Tracer implements [trace.TracerProvider].
+func (p *TracerProvider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	cfg := trace.NewTracerConfig(opts...)
+	key := newScopeKey(name, cfg.InstrumentationVersion(), cfg.SchemaURL(), cfg.InstrumentationAttributes())
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if t, ok := p.tracers[key]; ok {
+		return t
+	}
+	t := &tracer{name: name, opts: opts}
+	t.setDelegate(p.delegate)
+	p.tracers[key] = t
+	return t
+}
+
+// Set replaces underlying provider, returning previous one.
+func (p *TracerProvider) Set(delegate trace.TracerProvider) trace.TracerProvider {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	old := p.delegate
+	p.delegate = delegate
+	for _, t := range p.tracers {
+		t.setDelegate(delegate)
+	}
+	return old
+}
+
+type tracer struct {
+	embedded.Tracer
+
+	name     string
+	opts     []trace.TracerOption
+	delegate atomic.Pointer[trace.Tracer]
+}
+
+func (t *tracer) setDelegate(p trace.TracerProvider) {
+	d := p.Tracer(t.name, t.opts...)
+	t.delegate.Store(&d)
+}
+
+// Start implements [trace.Tracer].
+func (t *tracer) Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return (*t.delegate.Load()).Start(ctx, spanName, opts...)
+}
@@ -0,0 +1,272 @@
+// Package otlpjson implements OTLP/HTTP JSON encoding (http/json protocol)
+// on top of OTLP HTTP exporters that support only protobuf encoding.
+//
+// Requests are re-encoded from protobuf to JSON by [http.RoundTripper]
+// that is passed to exporter as HTTP client, responses are re-encoded back.
+package otlpjson
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+)
+
+const (
+	contentTypeJSON  = "application/json"
+	contentTypeProto = "application/x-protobuf"
+
+	// maxResponseSize limits size of response body, same as exporters do.
+	maxResponseSize = 64 * 1024 * 1024
+	// defaultTimeout is default export timeout of exporters.
+	defaultTimeout = 10 * time.Second
+)
+
+// message is OTLP export request or response.
+type message interface {
+	MarshalProto() ([]byte, error)
+	UnmarshalProto(data []byte) error
+	MarshalJSON() ([]byte, error)
+	UnmarshalJSON(data []byte) error
+}
+
+// Signal is OTLP signal type.
+type Signal struct {
+	name     string
+	request  func() message
+	response func() message
+}
+
+// Signals.
+var (
+	Traces = Signal{
+		name:     "traces",
+		request:  func() message { return ptraceotlp.NewExportRequest() },
+		response: func() message { return ptraceotlp.NewExportResponse() },
+	}
+	Metrics = Signal{
+		name:     "metrics",
+		request:  func() message { return pmetricotlp.NewExportRequest() },
+		response: func() message { return pmetricotlp.NewExportResponse() },
+	}
+	Logs = Signal{
+		name:     "logs",
+		request:  func() message { return plogotlp.NewExportRequest() },
+		response: func() message { return plogotlp.NewExportResponse() },
+	}
+)
+
+// Config is configuration of HTTP client that exporter would use if
+// WithHTTPClient option is not set.
+type Config struct {
+	// TLS is TLS configuration, nil means default.
+	TLS *tls.Config
+	// Proxy returns proxy URL of request, nil means [http.ProxyFromEnvironment].
+	Proxy func(*http.Request) (*url.URL, error)
+	// Timeout of export request, zero means default of 10s.
+	Timeout time.Duration
+}
+
+// ConfigFromEnv returns client configuration of signal s from
+// OTEL_EXPORTER_OTLP_{,<SIGNAL>_}{CERTIFICATE,CLIENT_CERTIFICATE,CLIENT_KEY,TIMEOUT}
+// environment variables, as exporters read them. Signal-specific variables
+// take precedence.
+func ConfigFromEnv(s Signal) (Config, error) {
+	var (
+		cfg    Config
+		prefix = "OTEL_EXPORTER_OTLP_" + strings.ToUpper(s.name) + "_"
+	)
+	lookup := func(name string) (string, string) {
+		if v := os.Getenv(prefix + name); v != "" {
+			return prefix + name, v
+		}
+		return "OTEL_EXPORTER_OTLP_" + name, os.Getenv("OTEL_EXPORTER_OTLP_" + name)
+	}
+
+	if name, v := lookup("TIMEOUT"); v != "" {
+		ms, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			return cfg, errors.Wrapf(err, "parse %s", name)
+		}
+		cfg.Timeout = time.Duration(ms) * time.Millisecond
+	}
+
+	var tlsCfg tls.Config
+	if name, v := lookup("CERTIFICATE"); v != "" {
+		data, err := os.ReadFile(v)
+		if err != nil {
+			return cfg, errors.Wrapf(err, "read %s", name)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return cfg, errors.Errorf("no certificates in %s", name)
+		}
+		tlsCfg.RootCAs = pool
+		cfg.TLS = &tlsCfg
+	}
+	certName, cert := lookup("CLIENT_CERTIFICATE")
+	keyName, key := lookup("CLIENT_KEY")
+	switch {
+	case cert != "" && key != "":
+		pair, err := tls.LoadX509KeyPair(cert, key)
+		if err != nil {
+			return cfg, errors.Wrapf(err, "load %s and %s", certName, keyName)
+		}
+		tlsCfg.Certificates = []tls.Certificate{pair}
+		cfg.TLS = &tlsCfg
+	case cert != "":
+		return cfg, errors.Errorf("%s is set without %s", certName, keyName)
+	case key != "":
+		return cfg, errors.Errorf("%s is set without %s", keyName, certName)
+	}
+	return cfg, nil
+}
+
+// NewClient returns HTTP client that sends OTLP requests of signal s
+// as JSON, with transport and timeout set from cfg.
+//
+// Client should be passed to exporter by WithHTTPClient option, which
+// takes precedence over TLS, proxy and timeout options of exporter.
+func NewClient(s Signal, cfg Config) *http.Client {
+	next := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.TLS != nil {
+		next.TLSClientConfig = cfg.TLS
+	}
+	if cfg.Proxy != nil {
+		next.Proxy = cfg.Proxy
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &http.Client{
+		Transport: NewTransport(s, next),
+		Timeout:   timeout,
+	}
+}
+
+// NewTransport returns [http.RoundTripper] that re-encodes protobuf OTLP
+// requests of signal s to JSON and JSON responses to protobuf.
+func NewTransport(s Signal, next http.RoundTripper) http.RoundTripper {
+	return &transport{signal: s, next: next}
+}
+
+type transport struct {
+	signal Signal
+	next   http.RoundTripper
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := t.encodeRequest(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "encode %s request", t.signal.name)
+	}
+
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	out.ContentLength = int64(len(body))
+	out.Header.Set("Content-Type", contentTypeJSON)
+
+	resp, err := t.next.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.decodeResponse(resp); err != nil {
+		_ = resp.Body.Close()
+		return nil, errors.Wrapf(err, "decode %s response", t.signal.name)
+	}
+	return resp, nil
+}
+
+// encodeRequest reads protobuf request body and returns it as JSON,
+// preserving compression.
+func (t *transport) encodeRequest(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, errors.New("no body")
+	}
+	defer func() { _ = req.Body.Close() }()
+
+	gzipped := req.Header.Get("Content-Encoding") == "gzip"
+	var r io.Reader = req.Body
+	if gzipped {
+		gr, err := gzip.NewReader(req.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "gzip")
+		}
+		defer func() { _ = gr.Close() }()
+		r = gr
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "read")
+	}
+
+	m := t.signal.request()
+	if err := m.UnmarshalProto(data); err != nil {
+		return nil, errors.Wrap(err, "unmarshal protobuf")
+	}
+	if data, err = m.MarshalJSON(); err != nil {
+		return nil, errors.Wrap(err, "marshal json")
+	}
+	if !gzipped {
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write(data); err != nil {
+		return nil, errors.Wrap(err, "gzip")
+	}
+	if err := gw.Close(); err != nil {
+		return nil, errors.Wrap(err, "gzip")
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeResponse replaces successful JSON response body with protobuf, so
+// exporter can report partial success.
+func (t *transport) decodeResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+	if ct, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); ct != contentTypeJSON {
+		return nil
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return errors.Wrap(err, "read")
+	}
+	_ = resp.Body.Close()
+
+	if len(bytes.TrimSpace(data)) > 0 {
+		m := t.signal.response()
+		if err := m.UnmarshalJSON(data); err != nil {
+			return errors.Wrap(err, "unmarshal json")
+		}
+		if data, err = m.MarshalProto(); err != nil {
+			return errors.Wrap(err, "marshal protobuf")
+		}
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	resp.ContentLength = int64(len(data))
+	resp.Header.Set("Content-Type", contentTypeProto)
+	resp.Header.Set("Content-Length", strconv.Itoa(len(data)))
+	return nil
+}
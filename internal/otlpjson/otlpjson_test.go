@@ -0,0 +1,210 @@
+package otlpjson
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+)
+
+func testRequest(t *testing.T) []byte {
+	t.Helper()
+	td := ptrace.NewTraces()
+	span := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetName("span")
+	span.SetTraceID(pcommon.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	data, err := ptraceotlp.NewExportRequestFromTraces(td).MarshalProto()
+	require.NoError(t, err)
+	return data
+}
+
+func TestTransport(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		gzip     bool
+		response string
+	}{
+		{name: "Plain", response: `{"partialSuccess":{"rejectedSpans":"1","errorMessage":"rejected"}}`},
+		{name: "Gzip", gzip: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var got ptrace.Traces
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				var body io.Reader = r.Body
+				if tt.gzip {
+					require.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+					gr, err := gzip.NewReader(r.Body)
+					require.NoError(t, err)
+					body = gr
+				}
+				data, err := io.ReadAll(body)
+				require.NoError(t, err)
+				require.Contains(t, string(data), `"traceId":"0102030405060708090a0b0c0d0e0f10"`)
+
+				req := ptraceotlp.NewExportRequest()
+				require.NoError(t, req.UnmarshalJSON(data))
+				got = req.Traces()
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = io.WriteString(w, tt.response)
+			}))
+			t.Cleanup(s.Close)
+
+			body := testRequest(t)
+			if tt.gzip {
+				var buf bytes.Buffer
+				gw := gzip.NewWriter(&buf)
+				_, err := gw.Write(body)
+				require.NoError(t, err)
+				require.NoError(t, gw.Close())
+				body = buf.Bytes()
+			}
+			req, err := http.NewRequest(http.MethodPost, s.URL+"/v1/traces", bytes.NewReader(body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/x-protobuf")
+			if tt.gzip {
+				req.Header.Set("Content-Encoding", "gzip")
+			}
+
+			resp, err := NewClient(Traces, Config{}).Do(req)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			require.Equal(t, "application/x-protobuf", resp.Header.Get("Content-Type"))
+			require.Equal(t, 1, got.SpanCount())
+
+			data, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			res := ptraceotlp.NewExportResponse()
+			require.NoError(t, res.UnmarshalProto(data))
+			if tt.response != "" {
+				require.Equal(t, int64(1), res.PartialSuccess().RejectedSpans())
+				require.Equal(t, "rejected", res.PartialSuccess().ErrorMessage())
+			}
+		})
+	}
+}
+
+func TestTransportInvalid(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("should not be called")
+	}))
+	t.Cleanup(s.Close)
+
+	req, err := http.NewRequest(http.MethodPost, s.URL, bytes.NewReader([]byte("\xff\xff")))
+	require.NoError(t, err)
+	_, err = NewClient(Traces, Config{}).Do(req)
+	require.Error(t, err)
+}
+
+// writePEM writes PEM block to temporary file and returns its path.
+func writePEM(t *testing.T, name, typ string, data []byte) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(p, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: data}), 0o600))
+	return p
+}
+
+// clientCertificate generates self-signed client certificate.
+func clientCertificate(t *testing.T) (*x509.Certificate, []byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return cert, der, keyDER
+}
+
+func TestClientTLS(t *testing.T) {
+	cert, certDER, keyDER := clientCertificate(t)
+	clients := x509.NewCertPool()
+	clients.AddCert(cert)
+
+	var called bool
+	s := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Len(t, r.TLS.PeerCertificates, 1)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	s.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clients,
+	}
+	s.StartTLS()
+	t.Cleanup(s.Close)
+
+	t.Setenv("OTEL_EXPORTER_OTLP_CERTIFICATE", writePEM(t, "ca.pem", "CERTIFICATE", s.Certificate().Raw))
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_CLIENT_CERTIFICATE", writePEM(t, "client.pem", "CERTIFICATE", certDER))
+	t.Setenv("OTEL_EXPORTER_OTLP_CLIENT_KEY", writePEM(t, "client.key", "EC PRIVATE KEY", keyDER))
+	t.Setenv("OTEL_EXPORTER_OTLP_TIMEOUT", "1000")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_TIMEOUT", "5000")
+
+	cfg, err := ConfigFromEnv(Traces)
+	require.NoError(t, err)
+	client := NewClient(Traces, cfg)
+	require.Equal(t, 5*time.Second, client.Timeout)
+
+	req, err := http.NewRequest(http.MethodPost, s.URL+"/v1/traces", bytes.NewReader(testRequest(t)))
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.True(t, called)
+
+	// Server certificate is not trusted by default.
+	req, err = http.NewRequest(http.MethodPost, s.URL+"/v1/traces", bytes.NewReader(testRequest(t)))
+	require.NoError(t, err)
+	_, err = NewClient(Traces, Config{}).Do(req)
+	require.Error(t, err)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	cfg, err := ConfigFromEnv(Logs)
+	require.NoError(t, err)
+	require.Equal(t, Config{}, cfg)
+	require.Equal(t, defaultTimeout, NewClient(Logs, cfg).Timeout)
+
+	for _, tt := range []struct {
+		name, value, err string
+	}{
+		{"OTEL_EXPORTER_OTLP_LOGS_TIMEOUT", "soon", "OTEL_EXPORTER_OTLP_LOGS_TIMEOUT"},
+		{"OTEL_EXPORTER_OTLP_CERTIFICATE", filepath.Join(t.TempDir(), "missing.pem"), "OTEL_EXPORTER_OTLP_CERTIFICATE"},
+		{"OTEL_EXPORTER_OTLP_CLIENT_KEY", "client.key", "OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.name, tt.value)
+			_, err := ConfigFromEnv(Logs)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
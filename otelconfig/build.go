@@ -0,0 +1,205 @@
+package otelconfig
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+	"strconv"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/contrib/propagators/autoprop"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func sortedKeys[V any](m map[string]V) []string {
+	return slices.Sorted(maps.Keys(m))
+}
+
+// KeyValue returns attribute as [attribute.KeyValue].
+func (a Attribute) KeyValue() (attribute.KeyValue, error) {
+	k := attribute.Key(a.Name)
+	if a.Value == nil {
+		return attribute.KeyValue{}, errors.New("required")
+	}
+	switch a.Type {
+	case "", "string":
+		return k.String(fmt.Sprint(a.Value)), nil
+	case "bool":
+		v, ok := a.Value.(bool)
+		if !ok {
+			return attribute.KeyValue{}, errors.Errorf("expected bool, got %T", a.Value)
+		}
+		return k.Bool(v), nil
+	case "int":
+		v, ok := a.Value.(int)
+		if !ok {
+			return attribute.KeyValue{}, errors.Errorf("expected int, got %T", a.Value)
+		}
+		return k.Int(v), nil
+	case "double":
+		switch v := a.Value.(type) {
+		case float64:
+			return k.Float64(v), nil
+		case int:
+			return k.Float64(float64(v)), nil
+		default:
+			return attribute.KeyValue{}, errors.Errorf("expected double, got %T", a.Value)
+		}
+	case "string_array":
+		v, err := toSlice(a.Value, func(v any) (string, bool) {
+			return fmt.Sprint(v), true
+		})
+		return k.StringSlice(v), err
+	case "bool_array":
+		v, err := toSlice(a.Value, func(v any) (bool, bool) {
+			b, ok := v.(bool)
+			return b, ok
+		})
+		return k.BoolSlice(v), err
+	case "int_array":
+		v, err := toSlice(a.Value, func(v any) (int, bool) {
+			i, ok := v.(int)
+			return i, ok
+		})
+		return k.IntSlice(v), err
+	case "double_array":
+		v, err := toSlice(a.Value, func(v any) (float64, bool) {
+			switch v := v.(type) {
+			case float64:
+				return v, true
+			case int:
+				return float64(v), true
+			default:
+				return 0, false
+			}
+		})
+		return k.Float64Slice(v), err
+	default:
+		return attribute.KeyValue{}, errors.Errorf("unsupported type %q", a.Type)
+	}
+}
+
+func toSlice[T any](v any, conv func(v any) (T, bool)) ([]T, error) {
+	list, ok := v.([]any)
+	if !ok {
+		return nil, errors.Errorf("expected sequence, got %T", v)
+	}
+	out := make([]T, 0, len(list))
+	for i, e := range list {
+		t, ok := conv(e)
+		if !ok {
+			return nil, errors.Errorf("[%d]: unexpected type %T", i, e)
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// NewResource returns resource from configuration.
+//
+// Returns nil if resource is not configured.
+func (c *Config) NewResource(ctx context.Context) (*resource.Resource, error) {
+	r := c.Resource
+	if r == nil {
+		return nil, nil
+	}
+	var attrs []attribute.KeyValue
+	// Validated on parse.
+	list, _ := parseList(r.AttributesList)
+	for _, kv := range list {
+		attrs = append(attrs, attribute.String(kv.key, kv.value))
+	}
+	for _, a := range r.Attributes {
+		kv, _ := a.KeyValue()
+		attrs = append(attrs, kv)
+	}
+	opts := []resource.Option{
+		resource.WithAttributes(attrs...),
+	}
+	if r.SchemaURL != "" {
+		opts = append(opts, resource.WithSchemaURL(r.SchemaURL))
+	}
+	res, err := resource.New(ctx, opts...)
+	if err != nil {
+		return nil, c.Wrap("resource", err)
+	}
+	return res, nil
+}
+
+// MergeResource merges res with configured resource, configured attributes
+// take precedence.
+func (c *Config) MergeResource(ctx context.Context, res *resource.Resource) (*resource.Resource, error) {
+	r, err := c.NewResource(ctx)
+	if err != nil || r == nil {
+		return res, err
+	}
+	if res == nil {
+		return r, nil
+	}
+	merged, err := resource.Merge(res, r)
+	if err != nil {
+		return nil, c.Wrap("resource", err)
+	}
+	return merged, nil
+}
+
+// TextMapPropagator returns configured propagator.
+//
+// Returns false if propagator is not configured.
+func (c *Config) TextMapPropagator() (propagation.TextMapPropagator, bool, error) {
+	p := c.Propagator
+	if p == nil {
+		return nil, false, nil
+	}
+	var names []string
+	seen := map[string]struct{}{}
+	add := func(name string) {
+		if _, ok := seen[name]; ok {
+			return
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	for _, name := range p.Composite {
+		add(string(name))
+	}
+	for _, name := range parseNames(p.CompositeList) {
+		add(name)
+	}
+	prop, err := autoprop.TextMapPropagator(names...)
+	if err != nil {
+		return nil, false, c.Wrap("propagator", err)
+	}
+	return prop, true, nil
+}
+
+// PrometheusAddr returns listen address of Prometheus pull exporter,
+// if configured with host or port.
+func (c *Config) PrometheusAddr() (string, bool) {
+	mp := c.MeterProvider
+	if mp == nil || c.Disabled {
+		return "", false
+	}
+	for _, r := range mp.Readers {
+		if r.Pull == nil || r.Pull.Exporter.Prometheus == nil {
+			continue
+		}
+		p := r.Pull.Exporter.Prometheus
+		if p.Host == "" && p.Port == 0 {
+			continue
+		}
+		host, port := "localhost", 9464
+		if p.Host != "" {
+			host = p.Host
+		}
+		if p.Port != 0 {
+			port = p.Port
+		}
+		return net.JoinHostPort(host, strconv.Itoa(port)), true
+	}
+	return "", false
+}
@@ -0,0 +1,396 @@
+// Package otelconfig implements OpenTelemetry declarative configuration file
+// format.
+//
+// Subset of the format is supported: resource, propagator, tracer, meter and
+// logger providers with processors, readers, exporters, samplers and views.
+// Environment variables are substituted in scalar values using ${NAME},
+// ${env:NAME} and ${NAME:-default} syntax, "$$" is escaped "$".
+//
+// See https://opentelemetry.io/docs/specs/otel/configuration/data-model/.
+package otelconfig
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/go-faster/yaml"
+)
+
+// EnvFile is environment variable that contains path to configuration file.
+const EnvFile = "OTEL_CONFIG_FILE"
+
+// Config is an OpenTelemetry declarative configuration.
+type Config struct {
+	FileFormat     string          `yaml:"file_format"`
+	Disabled       bool            `yaml:"disabled"`
+	Resource       *Resource       `yaml:"resource"`
+	Propagator     *Propagator     `yaml:"propagator"`
+	TracerProvider *TracerProvider `yaml:"tracer_provider"`
+	MeterProvider  *MeterProvider  `yaml:"meter_provider"`
+	LoggerProvider *LoggerProvider `yaml:"logger_provider"`
+
+	// positions of decoded nodes by path.
+	positions map[string]position
+}
+
+// Resource configures telemetry resource.
+type Resource struct {
+	Attributes []Attribute `yaml:"attributes"`
+	// AttributesList is a list of attributes in OTEL_RESOURCE_ATTRIBUTES format,
+	// Attributes take precedence.
+	AttributesList string `yaml:"attributes_list"`
+	SchemaURL      string `yaml:"schema_url"`
+}
+
+// Attribute is a resource attribute.
+type Attribute struct {
+	Name  string `yaml:"name"`
+	Value any    `yaml:"value"`
+	// Type of value, one of string, bool, int, double, string_array,
+	// bool_array, int_array, double_array. Defaults to string.
+	Type string `yaml:"type"`
+}
+
+// Propagator configures text map propagator.
+type Propagator struct {
+	Composite []PropagatorName `yaml:"composite"`
+	// CompositeList is a list of propagators in OTEL_PROPAGATORS format.
+	CompositeList string `yaml:"composite_list"`
+}
+
+// PropagatorName is a name of propagator.
+//
+// Both "- tracecontext" and "- tracecontext: {}" forms are accepted.
+type PropagatorName string
+
+// UnmarshalYAML implements [yaml.Unmarshaler].
+func (p *PropagatorName) UnmarshalYAML(n *yaml.Node) error {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		*p = PropagatorName(n.Value)
+		return nil
+	case yaml.MappingNode:
+		if len(n.Content) != 2 {
+			return errors.New("exactly one propagator must be set")
+		}
+		*p = PropagatorName(n.Content[0].Value)
+		return nil
+	default:
+		return errors.New("expected propagator name or mapping")
+	}
+}
+
+// TracerProvider configures tracer provider.
+type TracerProvider struct {
+	Processors []Processor `yaml:"processors"`
+	Sampler    *Sampler    `yaml:"sampler"`
+}
+
+// LoggerProvider configures logger provider.
+type LoggerProvider struct {
+	Processors []Processor `yaml:"processors"`
+}
+
+// Processor is a span or log record processor, exactly one field must be set.
+type Processor struct {
+	Batch  *BatchProcessor  `yaml:"batch"`
+	Simple *SimpleProcessor `yaml:"simple"`
+}
+
+// BatchProcessor configures batch processor.
+type BatchProcessor struct {
+	ScheduleDelay      *Milliseconds `yaml:"schedule_delay"`
+	ExportTimeout      *Milliseconds `yaml:"export_timeout"`
+	MaxQueueSize       *int          `yaml:"max_queue_size"`
+	MaxExportBatchSize *int          `yaml:"max_export_batch_size"`
+	Exporter           Exporter      `yaml:"exporter"`
+}
+
+// SimpleProcessor configures simple processor.
+type SimpleProcessor struct {
+	Exporter Exporter `yaml:"exporter"`
+}
+
+// Exporter configures exporter, exactly one exporter must be set.
+//
+// Exporters that are not known are stored in Custom by name and can
+// be created by LookupExporter option of corresponding package.
+type Exporter struct {
+	// OTLP exporter with protocol field.
+	OTLP       *OTLP       `yaml:"otlp"`
+	OTLPHTTP   *OTLP       `yaml:"otlp_http"`
+	OTLPGRPC   *OTLP       `yaml:"otlp_grpc"`
+	Console    *Console    `yaml:"console"`
+	Prometheus *Prometheus `yaml:"prometheus"`
+
+	Custom map[string]yaml.Node `yaml:",inline"`
+}
+
+// Exporter names.
+const (
+	ExporterOTLP       = "otlp"
+	ExporterOTLPHTTP   = "otlp_http"
+	ExporterOTLPGRPC   = "otlp_grpc"
+	ExporterConsole    = "console"
+	ExporterPrometheus = "prometheus"
+)
+
+// Names returns names of configured exporters.
+func (e Exporter) Names() []string {
+	var names []string
+	for _, v := range []struct {
+		name string
+		set  bool
+	}{
+		{ExporterOTLP, e.OTLP != nil},
+		{ExporterOTLPHTTP, e.OTLPHTTP != nil},
+		{ExporterOTLPGRPC, e.OTLPGRPC != nil},
+		{ExporterConsole, e.Console != nil},
+		{ExporterPrometheus, e.Prometheus != nil},
+	} {
+		if v.set {
+			names = append(names, v.name)
+		}
+	}
+	return append(names, slices.Sorted(maps.Keys(e.Custom))...)
+}
+
+// Name returns name of configured exporter.
+func (e Exporter) Name() string {
+	if names := e.Names(); len(names) == 1 {
+		return names[0]
+	}
+	return ""
+}
+
+// OTLP protocols.
+const (
+	ProtocolGRPC         = "grpc"
+	ProtocolHTTPProtobuf = "http/protobuf"
+	ProtocolHTTPJSON     = "http/json"
+)
+
+// OTLP HTTP encodings.
+const (
+	EncodingProtobuf = "protobuf"
+	EncodingJSON     = "json"
+)
+
+// OTLPConfig returns OTLP exporter configuration and its protocol, if
+// OTLP exporter is configured.
+func (e Exporter) OTLPConfig() (o *OTLP, protocol string, ok bool) {
+	switch {
+	case e.OTLP != nil:
+		protocol = e.OTLP.Protocol
+		if protocol == "" {
+			protocol = ProtocolGRPC
+		}
+		return e.OTLP, protocol, true
+	case e.OTLPHTTP != nil:
+		if e.OTLPHTTP.Encoding == EncodingJSON {
+			return e.OTLPHTTP, ProtocolHTTPJSON, true
+		}
+		return e.OTLPHTTP, ProtocolHTTPProtobuf, true
+	case e.OTLPGRPC != nil:
+		return e.OTLPGRPC, ProtocolGRPC, true
+	default:
+		return nil, "", false
+	}
+}
+
+// OTLP configures OTLP exporter.
+type OTLP struct {
+	// Protocol is only used by "otlp" exporter, defaults to grpc.
+	Protocol string `yaml:"protocol"`
+	// Encoding is only used by "otlp_http" exporter, protobuf or json,
+	// defaults to protobuf.
+	Encoding string `yaml:"encoding"`
+	// Endpoint is URL of collector, e.g. http://localhost:4318/v1/traces.
+	Endpoint string   `yaml:"endpoint"`
+	Headers  []Header `yaml:"headers"`
+	// HeadersList is a list of headers in OTEL_EXPORTER_OTLP_HEADERS format,
+	// Headers take precedence.
+	HeadersList string        `yaml:"headers_list"`
+	Compression string        `yaml:"compression"`
+	Timeout     *Milliseconds `yaml:"timeout"`
+	Insecure    bool          `yaml:"insecure"`
+
+	// TemporalityPreference is used only by metric exporter, one of
+	// cumulative, delta or low_memory.
+	TemporalityPreference string `yaml:"temporality_preference"`
+	// DefaultHistogramAggregation is used only by metric exporter, one of
+	// explicit_bucket_histogram or base2_exponential_bucket_histogram.
+	DefaultHistogramAggregation string `yaml:"default_histogram_aggregation"`
+}
+
+// HeaderMap returns all configured headers.
+func (o *OTLP) HeaderMap() map[string]string {
+	h := make(map[string]string, len(o.Headers))
+	// Validated on parse.
+	list, _ := parseList(o.HeadersList)
+	for _, kv := range list {
+		h[kv.key] = kv.value
+	}
+	for _, kv := range o.Headers {
+		h[kv.Name] = kv.Value
+	}
+	return h
+}
+
+// Header is an exporter header.
+type Header struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+// Console configures exporter that writes to stdout.
+type Console struct{}
+
+// Prometheus configures Prometheus pull exporter.
+type Prometheus struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+	// TranslationStrategy is one of UnderscoreEscapingWithSuffixes (default),
+	// UnderscoreEscapingWithoutSuffixes, NoUTF8EscapingWithSuffixes or
+	// NoTranslation.
+	TranslationStrategy string `yaml:"translation_strategy"`
+	WithoutScopeInfo    bool   `yaml:"without_scope_info"`
+	WithoutTargetInfo   bool   `yaml:"without_target_info"`
+	// WithResourceConstantLabels selects resource attributes that are added
+	// as labels to all metrics.
+	WithResourceConstantLabels *IncludeExclude `yaml:"with_resource_constant_labels"`
+	// Namespace is prefix of metric names.
+	//
+	// Not defined by specification.
+	Namespace string `yaml:"namespace"`
+	// LegacyCollectors registers process, Go and build info collectors of
+	// Prometheus client, enabled by default.
+	//
+	// Not defined by specification.
+	LegacyCollectors *bool `yaml:"legacy_collectors"`
+}
+
+// Prometheus translation strategies.
+const (
+	UnderscoreEscapingWithSuffixes    = "UnderscoreEscapingWithSuffixes"
+	UnderscoreEscapingWithoutSuffixes = "UnderscoreEscapingWithoutSuffixes"
+	NoUTF8EscapingWithSuffixes        = "NoUTF8EscapingWithSuffixes"
+	NoTranslation                     = "NoTranslation"
+)
+
+// Sampler configures sampler, exactly one field must be set.
+type Sampler struct {
+	AlwaysOn          *Empty             `yaml:"always_on"`
+	AlwaysOff         *Empty             `yaml:"always_off"`
+	TraceIDRatioBased *TraceIDRatioBased `yaml:"trace_id_ratio_based"`
+	ParentBased       *ParentBased       `yaml:"parent_based"`
+}
+
+// TraceIDRatioBased configures ratio sampler.
+type TraceIDRatioBased struct {
+	Ratio *float64 `yaml:"ratio"`
+}
+
+// ParentBased configures parent based sampler.
+type ParentBased struct {
+	Root                   *Sampler `yaml:"root"`
+	RemoteParentSampled    *Sampler `yaml:"remote_parent_sampled"`
+	RemoteParentNotSampled *Sampler `yaml:"remote_parent_not_sampled"`
+	LocalParentSampled     *Sampler `yaml:"local_parent_sampled"`
+	LocalParentNotSampled  *Sampler `yaml:"local_parent_not_sampled"`
+}
+
+// MeterProvider configures meter provider.
+type MeterProvider struct {
+	Readers []MetricReader `yaml:"readers"`
+	Views   []View         `yaml:"views"`
+	// ExemplarFilter is one of always_on, always_off or trace_based.
+	ExemplarFilter string `yaml:"exemplar_filter"`
+}
+
+// MetricReader configures metric reader, exactly one field must be set.
+type MetricReader struct {
+	Periodic *PeriodicReader `yaml:"periodic"`
+	Pull     *PullReader     `yaml:"pull"`
+}
+
+// PeriodicReader configures periodic metric reader.
+type PeriodicReader struct {
+	Interval *Milliseconds `yaml:"interval"`
+	Timeout  *Milliseconds `yaml:"timeout"`
+	Exporter Exporter      `yaml:"exporter"`
+}
+
+// PullReader configures pull metric reader.
+type PullReader struct {
+	Exporter Exporter `yaml:"exporter"`
+}
+
+// View configures metric view.
+type View struct {
+	Selector ViewSelector `yaml:"selector"`
+	Stream   ViewStream   `yaml:"stream"`
+}
+
+// ViewSelector selects instruments for view.
+type ViewSelector struct {
+	// InstrumentName supports "*" and "?" wildcards.
+	InstrumentName string `yaml:"instrument_name"`
+	// InstrumentType is one of counter, up_down_counter, histogram, gauge,
+	// observable_counter, observable_up_down_counter or observable_gauge.
+	InstrumentType string `yaml:"instrument_type"`
+	Unit           string `yaml:"unit"`
+	MeterName      string `yaml:"meter_name"`
+	MeterVersion   string `yaml:"meter_version"`
+	MeterSchemaURL string `yaml:"meter_schema_url"`
+}
+
+// ViewStream configures stream of selected instruments.
+type ViewStream struct {
+	Name          string          `yaml:"name"`
+	Description   string          `yaml:"description"`
+	Aggregation   *Aggregation    `yaml:"aggregation"`
+	AttributeKeys *IncludeExclude `yaml:"attribute_keys"`
+}
+
+// IncludeExclude filters by name.
+type IncludeExclude struct {
+	Included []string `yaml:"included"`
+	Excluded []string `yaml:"excluded"`
+}
+
+// Aggregation configures stream aggregation, exactly one field must be set.
+type Aggregation struct {
+	Default                         *Empty                           `yaml:"default"`
+	Drop                            *Empty                           `yaml:"drop"`
+	Sum                             *Empty                           `yaml:"sum"`
+	LastValue                       *Empty                           `yaml:"last_value"`
+	ExplicitBucketHistogram         *ExplicitBucketHistogram         `yaml:"explicit_bucket_histogram"`
+	Base2ExponentialBucketHistogram *Base2ExponentialBucketHistogram `yaml:"base2_exponential_bucket_histogram"`
+}
+
+// ExplicitBucketHistogram configures explicit bucket histogram aggregation.
+type ExplicitBucketHistogram struct {
+	Boundaries   []float64 `yaml:"boundaries"`
+	RecordMinMax *bool     `yaml:"record_min_max"`
+}
+
+// Base2ExponentialBucketHistogram configures exponential histogram aggregation.
+type Base2ExponentialBucketHistogram struct {
+	MaxScale     *int  `yaml:"max_scale"`
+	MaxSize      *int  `yaml:"max_size"`
+	RecordMinMax *bool `yaml:"record_min_max"`
+}
+
+// Empty is a configuration without fields.
+type Empty struct{}
+
+// Milliseconds is a duration in milliseconds.
+type Milliseconds int
+
+// Duration returns m as [time.Duration].
+func (m Milliseconds) Duration() time.Duration {
+	return time.Duration(m) * time.Millisecond
+}
@@ -0,0 +1,319 @@
+package otelconfig
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestLoad(t *testing.T) {
+	t.Setenv("SERVICE_NAME", "")
+	t.Setenv("ENVIRONMENT", "prod")
+	t.Setenv("COLLECTOR_ENDPOINT", "http://collector:4318")
+	t.Setenv("EXPORT_TIMEOUT", "5000")
+
+	c, err := Load(filepath.Join("_testdata", "config.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, "0.3", c.FileFormat)
+
+	res, err := c.NewResource(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "https://opentelemetry.io/schemas/1.27.0", res.SchemaURL())
+	require.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("service.name", "unknown"),
+		attribute.Int("service.instance.count", 3),
+		attribute.String("deployment.environment", "prod"),
+		attribute.String("team", "platform"),
+	}, res.Attributes())
+
+	prop, ok, err := c.TextMapPropagator()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.ElementsMatch(t, []string{"traceparent", "tracestate", "baggage"}, prop.Fields())
+
+	tp := c.TracerProvider
+	require.Len(t, tp.Processors, 2)
+	batch := tp.Processors[0].Batch
+	require.NotNil(t, batch)
+	require.Equal(t, time.Second, batch.ScheduleDelay.Duration())
+	require.Equal(t, 256, *batch.MaxExportBatchSize)
+	require.Nil(t, batch.MaxQueueSize)
+
+	o, proto, ok := batch.Exporter.OTLPConfig()
+	require.True(t, ok)
+	require.Equal(t, ProtocolHTTPProtobuf, proto)
+	require.Equal(t, "http://collector:4318/v1/traces", o.Endpoint)
+	require.Equal(t, 5*time.Second, o.Timeout.Duration())
+	require.Equal(t, map[string]string{"api-key": "${NOT_SUBSTITUTED}"}, o.HeaderMap())
+	require.Equal(t, ExporterConsole, tp.Processors[1].Simple.Exporter.Name())
+	require.Equal(t, 0.25, *tp.Sampler.ParentBased.Root.TraceIDRatioBased.Ratio)
+
+	mp := c.MeterProvider
+	require.Len(t, mp.Readers, 2)
+	addr, ok := c.PrometheusAddr()
+	require.True(t, ok)
+	require.Equal(t, "localhost:9090", addr)
+	o, proto, ok = mp.Readers[1].Periodic.Exporter.OTLPConfig()
+	require.True(t, ok)
+	require.Equal(t, ProtocolGRPC, proto)
+	require.True(t, o.Insecure)
+	require.Equal(t, "delta", o.TemporalityPreference)
+	require.Len(t, mp.Views, 1)
+	require.Equal(t, []float64{0.1, 0.5, 1, 5}, mp.Views[0].Stream.Aggregation.ExplicitBucketHistogram.Boundaries)
+	require.Equal(t, []string{"url.full"}, mp.Views[0].Stream.AttributeKeys.Excluded)
+
+	exp := c.LoggerProvider.Processors[0].Batch.Exporter
+	require.Equal(t, "kafka", exp.Name())
+	require.Contains(t, exp.Custom, "kafka")
+}
+
+func TestParseError(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		input  string
+		path   string
+		line   int
+		errMsg string
+	}{
+		{
+			name:   "NoFileFormat",
+			input:  "disabled: true",
+			path:   "file_format",
+			errMsg: "required",
+		},
+		{
+			name:   "UnknownField",
+			input:  "file_format: '0.3'\ntracer_provider:\n  processor: []",
+			path:   "tracer_provider.processor",
+			line:   3,
+			errMsg: `unknown field "processor"`,
+		},
+		{
+			name: "InvalidType",
+			input: `file_format: '0.3'
+tracer_provider:
+  processors:
+    - batch:
+        max_queue_size: many
+        exporter: {console: {}}`,
+			path:   "tracer_provider.processors[0].batch.max_queue_size",
+			line:   5,
+			errMsg: `invalid int value "many"`,
+		},
+		{
+			name: "MultipleExporters",
+			input: `file_format: '0.3'
+logger_provider:
+  processors:
+    - simple:
+        exporter:
+          console: {}
+          otlp_grpc: {}`,
+			path:   "logger_provider.processors[0].simple.exporter",
+			line:   6,
+			errMsg: "only one exporter must be set, got otlp_grpc, console",
+		},
+		{
+			name: "SamplerRatio",
+			input: `file_format: '0.3'
+tracer_provider:
+  sampler:
+    parent_based:
+      root:
+        trace_id_ratio_based:
+          ratio: 2`,
+			path:   "tracer_provider.sampler.parent_based.root.trace_id_ratio_based.ratio",
+			line:   7,
+			errMsg: "ratio 2 is out of [0, 1] range",
+		},
+		{
+			name: "PrometheusPush",
+			input: `file_format: '0.3'
+meter_provider:
+  readers:
+    - periodic:
+        exporter:
+          prometheus: {}`,
+			path:   "meter_provider.readers[0].periodic.exporter.prometheus",
+			line:   6,
+			errMsg: "only supported by pull metric reader",
+		},
+		{
+			name: "Encoding",
+			input: `file_format: '0.3'
+tracer_provider:
+  processors:
+    - batch:
+        exporter:
+          otlp_http:
+            encoding: xml`,
+			path:   "tracer_provider.processors[0].batch.exporter.otlp_http.encoding",
+			line:   7,
+			errMsg: `unsupported encoding "xml"`,
+		},
+		{
+			name: "TranslationStrategy",
+			input: `file_format: '0.3'
+meter_provider:
+  readers:
+    - pull:
+        exporter:
+          prometheus:
+            translation_strategy: Escaping`,
+			path:   "meter_provider.readers[0].pull.exporter.prometheus.translation_strategy",
+			line:   7,
+			errMsg: `unsupported translation strategy "Escaping"`,
+		},
+		{
+			name:   "Propagator",
+			input:  "file_format: '0.3'\npropagator:\n  composite: [foo]",
+			path:   "propagator",
+			line:   3,
+			errMsg: "foo",
+		},
+		{
+			name:   "Substitution",
+			input:  "file_format: '0.3'\nresource:\n  schema_url: ${1FOO}",
+			path:   "resource.schema_url",
+			line:   3,
+			errMsg: `invalid environment variable reference "${1FOO}"`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.input))
+			require.Error(t, err)
+			t.Log(err)
+
+			var cfgErr *Error
+			require.True(t, errors.As(err, &cfgErr))
+			require.Equal(t, tt.path, cfgErr.Path)
+			require.Equal(t, tt.line, cfgErr.Line)
+			require.ErrorContains(t, cfgErr.Err, tt.errMsg)
+		})
+	}
+}
+
+func TestExporterOTLPConfig(t *testing.T) {
+	for _, tt := range []struct {
+		exporter Exporter
+		protocol string
+	}{
+		{Exporter{OTLP: &OTLP{}}, ProtocolGRPC},
+		{Exporter{OTLP: &OTLP{Protocol: ProtocolHTTPJSON}}, ProtocolHTTPJSON},
+		{Exporter{OTLPHTTP: &OTLP{}}, ProtocolHTTPProtobuf},
+		{Exporter{OTLPHTTP: &OTLP{Encoding: EncodingJSON}}, ProtocolHTTPJSON},
+		{Exporter{OTLPGRPC: &OTLP{}}, ProtocolGRPC},
+	} {
+		_, protocol, ok := tt.exporter.OTLPConfig()
+		require.True(t, ok)
+		require.Equal(t, tt.protocol, protocol)
+	}
+}
+
+func TestSubstituteEnv(t *testing.T) {
+	env := map[string]string{
+		"FOO":   "foo",
+		"EMPTY": "",
+	}
+	lookup := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+	for _, tt := range []struct {
+		input  string
+		output string
+	}{
+		{"plain", "plain"},
+		{"${FOO}", "foo"},
+		{"${env:FOO}", "foo"},
+		{"a-${FOO}-b", "a-foo-b"},
+		{"${BAR}", ""},
+		{"${BAR:-bar}", "bar"},
+		{"${EMPTY:-def}", "def"},
+		{"${FOO:-def}", "foo"},
+		{"$$", "$"},
+		{"$${FOO}", "${FOO}"},
+		{"$$$${FOO}", "$${FOO}"},
+		{"$FOO", "$FOO"},
+		{"cost: 5$", "cost: 5$"},
+	} {
+		t.Run(tt.input, func(t *testing.T) {
+			v, err := substituteEnv(tt.input, lookup)
+			require.NoError(t, err)
+			require.Equal(t, tt.output, v)
+		})
+	}
+	for _, input := range []string{
+		"${FOO",
+		"${}",
+		"${FOO BAR}",
+	} {
+		_, err := substituteEnv(input, lookup)
+		require.Error(t, err, input)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv(EnvFile, "")
+	c, err := FromEnv()
+	require.NoError(t, err)
+	require.Nil(t, c)
+
+	name := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(name, []byte("file_format: '0.3'\ndisabled: ${DISABLED}"), 0o600))
+	t.Setenv(EnvFile, name)
+	t.Setenv("DISABLED", "true")
+
+	c, err = FromEnv()
+	require.NoError(t, err)
+	require.True(t, c.Disabled)
+}
+
+func TestLoadViews(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "views.yaml")
+	require.NoError(t, os.WriteFile(name, []byte(`views:
+  - selector:
+      instrument_name: http.server.request.duration
+    stream:
+      name: ${VIEW_NAME}
+      aggregation:
+        explicit_bucket_histogram:
+          boundaries: [0.1, 1, 10]
+  - selector:
+      meter_name: legacy
+    stream:
+      aggregation:
+        drop: {}
+`), 0o600))
+	t.Setenv("VIEW_NAME", "http.duration")
+
+	views, err := LoadViews(name)
+	require.NoError(t, err)
+	require.Len(t, views, 2)
+	require.Equal(t, "http.duration", views[0].Stream.Name)
+	require.Equal(t, []float64{0.1, 1, 10}, views[0].Stream.Aggregation.ExplicitBucketHistogram.Boundaries)
+	require.NotNil(t, views[1].Stream.Aggregation.Drop)
+
+	for _, tt := range []struct {
+		name  string
+		input string
+		path  string
+	}{
+		{"UnknownField", "view: []", "view"},
+		{"NoSelector", "views:\n  - stream:\n      name: foo", "views[0].selector"},
+		{"InstrumentType", "views:\n  - selector:\n      instrument_type: meter", "views[0].selector.instrument_type"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseViews([]byte(tt.input))
+			var cfgErr *Error
+			require.ErrorAs(t, err, &cfgErr)
+			require.Equal(t, tt.path, cfgErr.Path)
+		})
+	}
+}
@@ -0,0 +1,455 @@
+package otelconfig
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/go-faster/errors"
+	"github.com/go-faster/yaml"
+)
+
+// Error is a configuration error that points to the offending YAML path.
+type Error struct {
+	// Path is a path to the value, e.g. "tracer_provider.processors[0].batch".
+	Path   string
+	Line   int
+	Column int
+	Err    error
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Path)
+	if e.Line != 0 {
+		fmt.Fprintf(&b, " (line %d:%d)", e.Line, e.Column)
+	}
+	b.WriteString(": ")
+	b.WriteString(e.Err.Error())
+	return b.String()
+}
+
+// Unwrap returns underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+type position struct {
+	line, column int
+}
+
+// Errorf returns [Error] for value at path.
+func (c *Config) Errorf(path, format string, args ...any) error {
+	return c.Wrap(path, fmt.Errorf(format, args...))
+}
+
+// Wrap wraps err into [Error] for value at path.
+//
+// Returns nil if err is nil.
+func (c *Config) Wrap(path string, err error) error {
+	if err == nil {
+		return nil
+	}
+	e := &Error{Path: path, Err: err}
+	// Falling back to the closest parent that is present in file.
+	for p := path; p != ""; p = parentPath(p) {
+		if pos, ok := c.positions[p]; ok {
+			e.Line, e.Column = pos.line, pos.column
+			break
+		}
+	}
+	return e
+}
+
+func parentPath(p string) string {
+	if i := strings.LastIndexAny(p, ".["); i >= 0 {
+		return p[:i]
+	}
+	return ""
+}
+
+// FromEnv loads configuration from file set by OTEL_CONFIG_FILE environment variable.
+//
+// Returns nil if variable is not set.
+func FromEnv() (*Config, error) {
+	name := os.Getenv(EnvFile)
+	if name == "" {
+		return nil, nil
+	}
+	return Load(name)
+}
+
+// Load loads configuration from file.
+func Load(name string) (*Config, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, errors.Wrap(err, "read")
+	}
+	c, err := Parse(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse %q", name)
+	}
+	return c, nil
+}
+
+// Parse parses and validates configuration, substituting environment variables.
+func Parse(data []byte) (*Config, error) {
+	c := &Config{
+		positions: map[string]position{},
+	}
+	if err := c.decode(data, c); err != nil {
+		return nil, err
+	}
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// viewsFile is a file with metric views.
+type viewsFile struct {
+	Views []View `yaml:"views"`
+}
+
+// LoadViews loads metric views from file.
+//
+// File has the same format as meter_provider.views of configuration,
+// nested in top-level "views" key:
+//
+//	views:
+//	  - selector:
+//	      instrument_name: http.server.request.duration
+//	    stream:
+//	      attribute_keys:
+//	        excluded: [url.path]
+func LoadViews(name string) ([]View, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, errors.Wrap(err, "read")
+	}
+	views, err := ParseViews(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse %q", name)
+	}
+	return views, nil
+}
+
+// ParseViews parses and validates metric views file, substituting
+// environment variables.
+//
+// See [LoadViews] for format.
+func ParseViews(data []byte) ([]View, error) {
+	c := &Config{
+		positions: map[string]position{},
+	}
+	var f viewsFile
+	if err := c.decode(data, &f); err != nil {
+		return nil, err
+	}
+	for i, v := range f.Views {
+		if err := c.validateView(indexPath("views", i), v); err != nil {
+			return nil, err
+		}
+	}
+	return f.Views, nil
+}
+
+// decode decodes YAML document into v, recording positions in c.
+func (c *Config) decode(data []byte, v any) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return errors.Wrap(err, "yaml")
+	}
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 {
+		return errors.New("empty configuration")
+	}
+	d := &decoder{
+		lookupEnv: os.LookupEnv,
+		c:         c,
+	}
+	doc := root.Content[0]
+	if err := d.substitute("", doc); err != nil {
+		return err
+	}
+	return d.decode("", doc, reflect.ValueOf(v).Elem())
+}
+
+// decoder decodes nodes into configuration structs, reporting errors with path.
+type decoder struct {
+	lookupEnv func(string) (string, bool)
+	c         *Config
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func indexPath(path string, i int) string {
+	return fmt.Sprintf("%s[%d]", path, i)
+}
+
+func (d *decoder) errorf(path string, n *yaml.Node, format string, args ...any) error {
+	return &Error{
+		Path:   path,
+		Line:   n.Line,
+		Column: n.Column,
+		Err:    fmt.Errorf(format, args...),
+	}
+}
+
+// substitute replaces environment variable references in scalar values.
+//
+// Mapping keys are not substituted.
+func (d *decoder) substitute(path string, n *yaml.Node) error {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		if !strings.Contains(n.Value, "$") {
+			return nil
+		}
+		v, err := substituteEnv(n.Value, d.lookupEnv)
+		if err != nil {
+			return d.errorf(path, n, "%v", err)
+		}
+		n.Value = v
+		if n.Style == 0 {
+			// Plain scalar, resolving type of substituted value, like "${PORT}" to int.
+			n.Tag = ""
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if err := d.substitute(joinPath(path, n.Content[i].Value), n.Content[i+1]); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for i, v := range n.Content {
+			if err := d.substitute(indexPath(path, i), v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// substituteEnv replaces ${NAME}, ${env:NAME} and ${NAME:-default} in s.
+func substituteEnv(s string, lookupEnv func(string) (string, bool)) (string, error) {
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(s, '$')
+		if i < 0 || i == len(s)-1 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		b.WriteString(s[:i])
+		s = s[i:]
+		switch s[1] {
+		case '$':
+			// Escaped.
+			b.WriteByte('$')
+			s = s[2:]
+			continue
+		case '{':
+		default:
+			b.WriteByte('$')
+			s = s[1:]
+			continue
+		}
+		end := strings.IndexByte(s, '}')
+		if end < 0 {
+			return "", errors.Errorf("unterminated reference %q", s)
+		}
+		ref := s[2:end]
+		s = s[end+1:]
+
+		name, def, hasDefault := strings.Cut(ref, ":-")
+		name = strings.TrimPrefix(name, "env:")
+		if !validEnvName(name) {
+			return "", errors.Errorf("invalid environment variable reference %q", "${"+ref+"}")
+		}
+		v, ok := lookupEnv(name)
+		if (!ok || v == "") && hasDefault {
+			v = def
+		}
+		b.WriteString(v)
+	}
+}
+
+func validEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	unmarshalerType = reflect.TypeFor[yaml.Unmarshaler]()
+	nodeType        = reflect.TypeFor[yaml.Node]()
+)
+
+func isNull(n *yaml.Node) bool {
+	return n.Kind == yaml.ScalarNode && n.ShortTag() == "!!null"
+}
+
+// decode decodes node n at path into v.
+func (d *decoder) decode(path string, n *yaml.Node, v reflect.Value) error {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	d.c.positions[path] = position{line: n.Line, column: n.Column}
+
+	if v.CanAddr() && v.Addr().Type().Implements(unmarshalerType) {
+		if err := v.Addr().Interface().(yaml.Unmarshaler).UnmarshalYAML(n); err != nil {
+			return d.errorf(path, n, "%v", err)
+		}
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		if isNull(n) && v.Type().Elem().Kind() != reflect.Struct {
+			// Explicit null for scalar.
+			return nil
+		}
+		// Allocating struct even for null, so "console:" is same as "console: {}".
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.decode(path, n, v.Elem())
+	case reflect.Struct:
+		if v.Type() == nodeType {
+			v.Set(reflect.ValueOf(*n))
+			return nil
+		}
+		return d.decodeStruct(path, n, v)
+	case reflect.Slice:
+		if isNull(n) {
+			return nil
+		}
+		if n.Kind != yaml.SequenceNode {
+			return d.errorf(path, n, "expected sequence, got %s", n.ShortTag())
+		}
+		s := reflect.MakeSlice(v.Type(), len(n.Content), len(n.Content))
+		for i, e := range n.Content {
+			if err := d.decode(indexPath(path, i), e, s.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	case reflect.Interface:
+		if err := n.Decode(v.Addr().Interface()); err != nil {
+			return d.errorf(path, n, "%v", err)
+		}
+		return nil
+	default:
+		if n.Kind != yaml.ScalarNode {
+			return d.errorf(path, n, "expected %s, got %s", v.Kind(), n.ShortTag())
+		}
+		if err := n.Decode(v.Addr().Interface()); err != nil {
+			return d.errorf(path, n, "invalid %s value %q", v.Kind(), n.Value)
+		}
+		return nil
+	}
+}
+
+func (d *decoder) decodeStruct(path string, n *yaml.Node, v reflect.Value) error {
+	if isNull(n) {
+		return nil
+	}
+	if n.Kind != yaml.MappingNode {
+		return d.errorf(path, n, "expected mapping, got %s", n.ShortTag())
+	}
+	var (
+		t      = v.Type()
+		fields = map[string]int{}
+		inline = -1
+	)
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opt, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if opt == "inline" {
+			inline = i
+			continue
+		}
+		fields[name] = i
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		var (
+			k   = n.Content[i]
+			val = n.Content[i+1]
+			p   = joinPath(path, k.Value)
+		)
+		if idx, ok := fields[k.Value]; ok {
+			if err := d.decode(p, val, v.Field(idx)); err != nil {
+				return err
+			}
+			continue
+		}
+		if inline < 0 {
+			return d.errorf(p, k, "unknown field %q", k.Value)
+		}
+		m := v.Field(inline)
+		if m.IsNil() {
+			m.Set(reflect.MakeMap(m.Type()))
+		}
+		e := reflect.New(m.Type().Elem()).Elem()
+		if err := d.decode(p, val, e); err != nil {
+			return err
+		}
+		m.SetMapIndex(reflect.ValueOf(k.Value), e)
+	}
+	return nil
+}
+
+type keyValue struct {
+	key, value string
+}
+
+// parseList parses comma-separated list of key=value pairs with
+// url-encoded values, like OTEL_RESOURCE_ATTRIBUTES.
+func parseList(s string) ([]keyValue, error) {
+	var list []keyValue
+	for part := range strings.SplitSeq(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(part, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, errors.Errorf("invalid key-value pair %q", part)
+		}
+		value, err := url.PathUnescape(strings.TrimSpace(v))
+		if err != nil {
+			return nil, errors.Wrapf(err, "unescape %q", k)
+		}
+		list = append(list, keyValue{key: k, value: value})
+	}
+	return list, nil
+}
+
+// parseNames parses comma-separated list of names.
+func parseNames(s string) []string {
+	var names []string
+	for name := range strings.SplitSeq(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
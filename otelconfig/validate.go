@@ -0,0 +1,275 @@
+package otelconfig
+
+import (
+	"strings"
+)
+
+func (c *Config) validate() error {
+	if c.FileFormat == "" {
+		return c.Errorf("file_format", "required")
+	}
+	if major, _, _ := strings.Cut(c.FileFormat, "."); major != "0" && major != "1" {
+		return c.Errorf("file_format", "unsupported version %q", c.FileFormat)
+	}
+	if r := c.Resource; r != nil {
+		if err := c.validateResource("resource", r); err != nil {
+			return err
+		}
+	}
+	if c.Propagator != nil {
+		if _, _, err := c.TextMapPropagator(); err != nil {
+			return err
+		}
+	}
+	if tp := c.TracerProvider; tp != nil {
+		if err := c.validateProcessors("tracer_provider.processors", tp.Processors); err != nil {
+			return err
+		}
+		if s := tp.Sampler; s != nil {
+			if err := c.validateSampler("tracer_provider.sampler", s); err != nil {
+				return err
+			}
+		}
+	}
+	if mp := c.MeterProvider; mp != nil {
+		if err := c.validateMeterProvider("meter_provider", mp); err != nil {
+			return err
+		}
+	}
+	if lp := c.LoggerProvider; lp != nil {
+		if err := c.validateProcessors("logger_provider.processors", lp.Processors); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exactlyOne checks that exactly one of named values is set.
+func (c *Config) exactlyOne(path string, set map[string]bool) error {
+	var names []string
+	for name, ok := range set {
+		if ok {
+			names = append(names, name)
+		}
+	}
+	switch len(names) {
+	case 1:
+		return nil
+	case 0:
+		return c.Errorf(path, "one of %s must be set", strings.Join(sortedKeys(set), ", "))
+	default:
+		return c.Errorf(path, "only one of %s must be set", strings.Join(sortedKeys(set), ", "))
+	}
+}
+
+func (c *Config) validateResource(path string, r *Resource) error {
+	for i, a := range r.Attributes {
+		p := indexPath(joinPath(path, "attributes"), i)
+		if a.Name == "" {
+			return c.Errorf(joinPath(p, "name"), "required")
+		}
+		if _, err := a.KeyValue(); err != nil {
+			return c.Wrap(joinPath(p, "value"), err)
+		}
+	}
+	if _, err := parseList(r.AttributesList); err != nil {
+		return c.Wrap(joinPath(path, "attributes_list"), err)
+	}
+	return nil
+}
+
+func (c *Config) validateProcessors(path string, processors []Processor) error {
+	for i, p := range processors {
+		path := indexPath(path, i)
+		if err := c.exactlyOne(path, map[string]bool{
+			"batch":  p.Batch != nil,
+			"simple": p.Simple != nil,
+		}); err != nil {
+			return err
+		}
+		var e Exporter
+		switch {
+		case p.Batch != nil:
+			path = joinPath(path, "batch")
+			e = p.Batch.Exporter
+		case p.Simple != nil:
+			path = joinPath(path, "simple")
+			e = p.Simple.Exporter
+		}
+		path = joinPath(path, "exporter")
+		if err := c.validateExporter(path, e); err != nil {
+			return err
+		}
+		if e.Prometheus != nil {
+			return c.Errorf(joinPath(path, ExporterPrometheus), "only supported by pull metric reader")
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateExporter(path string, e Exporter) error {
+	switch names := e.Names(); len(names) {
+	case 0:
+		return c.Errorf(path, "exporter must be set")
+	case 1:
+	default:
+		return c.Errorf(path, "only one exporter must be set, got %s", strings.Join(names, ", "))
+	}
+	o, protocol, ok := e.OTLPConfig()
+	if !ok {
+		return nil
+	}
+	path = joinPath(path, e.Name())
+	switch protocol {
+	case ProtocolGRPC, ProtocolHTTPProtobuf, ProtocolHTTPJSON:
+	default:
+		return c.Errorf(joinPath(path, "protocol"), "unsupported protocol %q", protocol)
+	}
+	switch o.Encoding {
+	case "", EncodingProtobuf, EncodingJSON:
+	default:
+		return c.Errorf(joinPath(path, "encoding"), "unsupported encoding %q", o.Encoding)
+	}
+	switch o.Compression {
+	case "", "gzip", "none":
+	default:
+		return c.Errorf(joinPath(path, "compression"), "unsupported compression %q", o.Compression)
+	}
+	if _, err := parseList(o.HeadersList); err != nil {
+		return c.Wrap(joinPath(path, "headers_list"), err)
+	}
+	switch o.TemporalityPreference {
+	case "", "cumulative", "delta", "low_memory":
+	default:
+		return c.Errorf(joinPath(path, "temporality_preference"), "unsupported temporality %q", o.TemporalityPreference)
+	}
+	switch o.DefaultHistogramAggregation {
+	case "", "explicit_bucket_histogram", "base2_exponential_bucket_histogram":
+	default:
+		return c.Errorf(joinPath(path, "default_histogram_aggregation"), "unsupported aggregation %q", o.DefaultHistogramAggregation)
+	}
+	return nil
+}
+
+func (c *Config) validateSampler(path string, s *Sampler) error {
+	if err := c.exactlyOne(path, map[string]bool{
+		"always_on":            s.AlwaysOn != nil,
+		"always_off":           s.AlwaysOff != nil,
+		"trace_id_ratio_based": s.TraceIDRatioBased != nil,
+		"parent_based":         s.ParentBased != nil,
+	}); err != nil {
+		return err
+	}
+	if r := s.TraceIDRatioBased; r != nil && r.Ratio != nil {
+		if v := *r.Ratio; v < 0 || v > 1 {
+			return c.Errorf(joinPath(path, "trace_id_ratio_based.ratio"), "ratio %v is out of [0, 1] range", v)
+		}
+	}
+	if pb := s.ParentBased; pb != nil {
+		path := joinPath(path, "parent_based")
+		for _, v := range []struct {
+			name    string
+			sampler *Sampler
+		}{
+			{"root", pb.Root},
+			{"remote_parent_sampled", pb.RemoteParentSampled},
+			{"remote_parent_not_sampled", pb.RemoteParentNotSampled},
+			{"local_parent_sampled", pb.LocalParentSampled},
+			{"local_parent_not_sampled", pb.LocalParentNotSampled},
+		} {
+			if v.sampler == nil {
+				continue
+			}
+			if err := c.validateSampler(joinPath(path, v.name), v.sampler); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateMeterProvider(path string, mp *MeterProvider) error {
+	for i, r := range mp.Readers {
+		path := indexPath(joinPath(path, "readers"), i)
+		if err := c.exactlyOne(path, map[string]bool{
+			"periodic": r.Periodic != nil,
+			"pull":     r.Pull != nil,
+		}); err != nil {
+			return err
+		}
+		switch {
+		case r.Periodic != nil:
+			path := joinPath(path, "periodic.exporter")
+			if err := c.validateExporter(path, r.Periodic.Exporter); err != nil {
+				return err
+			}
+			if r.Periodic.Exporter.Prometheus != nil {
+				return c.Errorf(joinPath(path, ExporterPrometheus), "only supported by pull metric reader")
+			}
+		case r.Pull != nil:
+			path := joinPath(path, "pull.exporter")
+			if err := c.validateExporter(path, r.Pull.Exporter); err != nil {
+				return err
+			}
+			if _, _, ok := r.Pull.Exporter.OTLPConfig(); ok || r.Pull.Exporter.Console != nil {
+				return c.Errorf(joinPath(path, r.Pull.Exporter.Name()), "only supported by periodic metric reader")
+			}
+			if p := r.Pull.Exporter.Prometheus; p != nil {
+				switch p.TranslationStrategy {
+				case "", UnderscoreEscapingWithSuffixes, UnderscoreEscapingWithoutSuffixes,
+					NoUTF8EscapingWithSuffixes, NoTranslation:
+				default:
+					return c.Errorf(joinPath(path, ExporterPrometheus+".translation_strategy"),
+						"unsupported translation strategy %q", p.TranslationStrategy)
+				}
+			}
+		}
+	}
+	for i, v := range mp.Views {
+		if err := c.validateView(indexPath(joinPath(path, "views"), i), v); err != nil {
+			return err
+		}
+	}
+	switch mp.ExemplarFilter {
+	case "", "always_on", "always_off", "trace_based":
+	default:
+		return c.Errorf(joinPath(path, "exemplar_filter"), "unsupported exemplar filter %q", mp.ExemplarFilter)
+	}
+	return nil
+}
+
+func (c *Config) validateView(path string, v View) error {
+	if v.Selector == (ViewSelector{}) {
+		return c.Errorf(joinPath(path, "selector"), "at least one selector criteria must be set")
+	}
+	switch v.Selector.InstrumentType {
+	case "", "counter", "up_down_counter", "histogram", "gauge",
+		"observable_counter", "observable_up_down_counter", "observable_gauge":
+	default:
+		return c.Errorf(joinPath(path, "selector.instrument_type"), "unsupported instrument type %q", v.Selector.InstrumentType)
+	}
+	if v.Stream.Name != "" && strings.ContainsAny(v.Selector.InstrumentName, "*?") {
+		return c.Errorf(joinPath(path, "stream.name"), "can't be set for wildcard instrument name")
+	}
+	if a := v.Stream.Aggregation; a != nil {
+		path := joinPath(path, "stream.aggregation")
+		if err := c.exactlyOne(path, map[string]bool{
+			"default":                            a.Default != nil,
+			"drop":                               a.Drop != nil,
+			"sum":                                a.Sum != nil,
+			"last_value":                         a.LastValue != nil,
+			"explicit_bucket_histogram":          a.ExplicitBucketHistogram != nil,
+			"base2_exponential_bucket_histogram": a.Base2ExponentialBucketHistogram != nil,
+		}); err != nil {
+			return err
+		}
+		if h := a.ExplicitBucketHistogram; h != nil {
+			for i := 1; i < len(h.Boundaries); i++ {
+				if h.Boundaries[i] <= h.Boundaries[i-1] {
+					return c.Errorf(indexPath(joinPath(path, "explicit_bucket_histogram.boundaries"), i), "boundaries must be increasing")
+				}
+			}
+		}
+	}
+	return nil
+}
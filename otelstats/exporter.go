@@ -0,0 +1,106 @@
+package otelstats
+
+import (
+	"context"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SpanExporter wraps span exporter with given name to collect statistics.
+func (s *Stats) SpanExporter(name string, e sdktrace.SpanExporter) sdktrace.SpanExporter {
+	if s == nil {
+		return e
+	}
+	return &spanExporter{SpanExporter: e, stats: s.entry(SignalTraces, name)}
+}
+
+type spanExporter struct {
+	sdktrace.SpanExporter
+	stats *entry
+	queue *queue
+}
+
+func (e *spanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.queue.release(len(spans))
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	e.stats.export(len(spans), err)
+	return err
+}
+
+// LogExporter wraps log exporter with given name to collect statistics.
+func (s *Stats) LogExporter(name string, e sdklog.Exporter) sdklog.Exporter {
+	if s == nil {
+		return e
+	}
+	return &logExporter{Exporter: e, stats: s.entry(SignalLogs, name)}
+}
+
+type logExporter struct {
+	sdklog.Exporter
+	stats *entry
+	queue *queue
+}
+
+func (e *logExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	if len(records) == 0 {
+		// Flushing empty queue.
+		return e.Exporter.Export(ctx, records)
+	}
+	e.queue.release(len(records))
+	err := e.Exporter.Export(ctx, records)
+	e.stats.export(len(records), err)
+	return err
+}
+
+// MetricExporter wraps metric exporter with given name to collect statistics.
+//
+// Exported items are metric data points.
+func (s *Stats) MetricExporter(name string, e sdkmetric.Exporter) sdkmetric.Exporter {
+	if s == nil {
+		return e
+	}
+	return &metricExporter{Exporter: e, stats: s.entry(SignalMetrics, name)}
+}
+
+type metricExporter struct {
+	sdkmetric.Exporter
+	stats *entry
+}
+
+func (e *metricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	err := e.Exporter.Export(ctx, rm)
+	e.stats.export(dataPoints(rm), err)
+	return err
+}
+
+// dataPoints returns number of data points in rm.
+func dataPoints(rm *metricdata.ResourceMetrics) (n int) {
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Gauge[int64]:
+				n += len(data.DataPoints)
+			case metricdata.Gauge[float64]:
+				n += len(data.DataPoints)
+			case metricdata.Sum[int64]:
+				n += len(data.DataPoints)
+			case metricdata.Sum[float64]:
+				n += len(data.DataPoints)
+			case metricdata.Histogram[int64]:
+				n += len(data.DataPoints)
+			case metricdata.Histogram[float64]:
+				n += len(data.DataPoints)
+			case metricdata.ExponentialHistogram[int64]:
+				n += len(data.DataPoints)
+			case metricdata.ExponentialHistogram[float64]:
+				n += len(data.DataPoints)
+			case metricdata.Summary:
+				n += len(data.DataPoints)
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,171 @@
+package otelstats
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync/atomic"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// defaultQueueSize is default maximum queue size of batch span and log
+// record processors.
+const defaultQueueSize = 2048
+
+// queueSizeOr returns explicit size or size from environment variable.
+func queueSizeOr(size int, env string) int {
+	if size > 0 {
+		return size
+	}
+	if v, err := strconv.Atoi(os.Getenv(env)); err == nil && v > 0 {
+		return v
+	}
+	return defaultQueueSize
+}
+
+// queue tracks items accepted by batch processor and not yet passed to exporter.
+//
+// Processor queue is bounded by same size, so items are dropped by queue
+// before processor drops them silently.
+type queue struct {
+	stats   *entry
+	size    int64
+	pending atomic.Int64
+	stopped atomic.Bool
+}
+
+func newQueue(stats *entry, size int) *queue {
+	stats.capacity.Add(int64(size))
+	return &queue{stats: stats, size: int64(size)}
+}
+
+// acquire reserves place for item, returning false if queue is full.
+func (q *queue) acquire() bool {
+	if q.stopped.Load() {
+		// Processor ignores items after shutdown.
+		return false
+	}
+	for {
+		n := q.pending.Load()
+		if n >= q.size {
+			q.stats.dropped.Add(1)
+			return false
+		}
+		if q.pending.CompareAndSwap(n, n+1) {
+			q.stats.queued.Add(1)
+			return true
+		}
+	}
+}
+
+// release frees n places.
+func (q *queue) release(n int) {
+	if q == nil || q.stopped.Load() {
+		return
+	}
+	q.pending.Add(-int64(n))
+	q.stats.queued.Add(-int64(n))
+}
+
+// stop removes queue from statistics.
+func (q *queue) stop() {
+	if q.stopped.Swap(true) {
+		return
+	}
+	q.stats.capacity.Add(-q.size)
+	// Items that were not exported on shutdown.
+	n := q.pending.Swap(0)
+	q.stats.queued.Add(-n)
+}
+
+// BatchSpanProcessor creates [sdktrace.NewBatchSpanProcessor] for exporter
+// with given name, collecting statistics of exporter and processor queue.
+//
+// If queueSize is zero, OTEL_BSP_MAX_QUEUE_SIZE environment variable or
+// default queue size is used.
+func (s *Stats) BatchSpanProcessor(
+	name string,
+	e sdktrace.SpanExporter,
+	queueSize int,
+	opts ...sdktrace.BatchSpanProcessorOption,
+) sdktrace.SpanProcessor {
+	if s == nil {
+		return sdktrace.NewBatchSpanProcessor(e, opts...)
+	}
+	var (
+		stats = s.entry(SignalTraces, name)
+		q     = newQueue(stats, queueSizeOr(queueSize, "OTEL_BSP_MAX_QUEUE_SIZE"))
+		exp   = &spanExporter{SpanExporter: e, stats: stats, queue: q}
+	)
+	opts = append(opts, sdktrace.WithMaxQueueSize(int(q.size)))
+	return &spanProcessor{
+		SpanProcessor: sdktrace.NewBatchSpanProcessor(exp, opts...),
+		queue:         q,
+	}
+}
+
+type spanProcessor struct {
+	sdktrace.SpanProcessor
+	queue *queue
+}
+
+func (p *spanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() {
+		// Ignored by batch processor.
+		return
+	}
+	if !p.queue.acquire() {
+		return
+	}
+	p.SpanProcessor.OnEnd(s)
+}
+
+func (p *spanProcessor) Shutdown(ctx context.Context) error {
+	defer p.queue.stop()
+	return p.SpanProcessor.Shutdown(ctx)
+}
+
+// BatchLogProcessor creates [sdklog.NewBatchProcessor] for exporter with
+// given name, collecting statistics of exporter and processor queue.
+//
+// If queueSize is zero, OTEL_BLRP_MAX_QUEUE_SIZE environment variable or
+// default queue size is used.
+func (s *Stats) BatchLogProcessor(
+	name string,
+	e sdklog.Exporter,
+	queueSize int,
+	opts ...sdklog.BatchProcessorOption,
+) sdklog.Processor {
+	if s == nil {
+		return sdklog.NewBatchProcessor(e, opts...)
+	}
+	var (
+		stats = s.entry(SignalLogs, name)
+		q     = newQueue(stats, queueSizeOr(queueSize, "OTEL_BLRP_MAX_QUEUE_SIZE"))
+		exp   = &logExporter{Exporter: e, stats: stats, queue: q}
+	)
+	opts = append(opts, sdklog.WithMaxQueueSize(int(q.size)))
+	return &logProcessor{
+		Processor: sdklog.NewBatchProcessor(exp, opts...),
+		queue:     q,
+	}
+}
+
+type logProcessor struct {
+	sdklog.Processor
+	queue *queue
+}
+
+func (p *logProcessor) OnEmit(ctx context.Context, r *sdklog.Record) error {
+	if !p.queue.acquire() {
+		return nil
+	}
+	return p.Processor.OnEmit(ctx, r)
+}
+
+func (p *logProcessor) Shutdown(ctx context.Context) error {
+	defer p.queue.stop()
+	return p.Processor.Shutdown(ctx)
+}
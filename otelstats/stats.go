@@ -0,0 +1,186 @@
+// Package otelstats implements self-observability of OpenTelemetry SDK
+// pipelines: export attempts and failures, exported and dropped items,
+// processor queue length and time of last successful export.
+//
+// Exporters and batch processors are wrapped by [Stats] and metrics are
+// reported by [Stats.Register].
+package otelstats
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-faster/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Signals.
+const (
+	SignalTraces  = "traces"
+	SignalMetrics = "metrics"
+	SignalLogs    = "logs"
+)
+
+type key struct {
+	signal   string
+	exporter string
+}
+
+// entry is statistics of single exporter.
+type entry struct {
+	attrs attribute.Set
+
+	exports     atomic.Int64
+	failures    atomic.Int64
+	exported    atomic.Int64
+	failed      atomic.Int64
+	dropped     atomic.Int64
+	queued      atomic.Int64
+	capacity    atomic.Int64
+	lastSuccess atomic.Int64 // unix nano
+}
+
+func (e *entry) export(items int, err error) {
+	e.exports.Add(1)
+	if err != nil {
+		e.failures.Add(1)
+		e.failed.Add(int64(items))
+		return
+	}
+	e.exported.Add(int64(items))
+	e.lastSuccess.Store(time.Now().UnixNano())
+}
+
+// Stats collects statistics of wrapped exporters.
+//
+// Statistics are kept per signal and exporter name and are shared by all
+// exporters with same name, so they persist if providers are re-created.
+//
+// Nil *Stats is valid and returns exporters and processors as is.
+type Stats struct {
+	mux     sync.Mutex
+	entries map[key]*entry
+}
+
+// New creates new [Stats].
+func New() *Stats {
+	return &Stats{entries: map[key]*entry{}}
+}
+
+func (s *Stats) entry(signal, exporter string) *entry {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	k := key{signal: signal, exporter: exporter}
+	e, ok := s.entries[k]
+	if !ok {
+		e = &entry{
+			attrs: attribute.NewSet(
+				attribute.String("signal", signal),
+				attribute.String("exporter", exporter),
+			),
+		}
+		s.entries[k] = e
+	}
+	return e
+}
+
+func (s *Stats) snapshot() []*entry {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	out := make([]*entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		out = append(out, e)
+	}
+	return out
+}
+
+// Register registers instruments reporting statistics on given meter provider.
+//
+// All instruments have "signal" and "exporter" attributes.
+func (s *Stats) Register(mp metric.MeterProvider) error {
+	meter := mp.Meter("github.com/go-faster/sdk/otelstats")
+
+	exports, err := meter.Int64ObservableCounter("otel.sdk.exports",
+		metric.WithDescription("Number of export attempts"),
+		metric.WithUnit("{export}"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "exports")
+	}
+	failures, err := meter.Int64ObservableCounter("otel.sdk.export.failures",
+		metric.WithDescription("Number of failed export attempts"),
+		metric.WithUnit("{export}"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failures")
+	}
+	exported, err := meter.Int64ObservableCounter("otel.sdk.exported",
+		metric.WithDescription("Number of successfully exported spans, log records or metric data points"),
+		metric.WithUnit("{item}"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "exported")
+	}
+	failed, err := meter.Int64ObservableCounter("otel.sdk.export.failed",
+		metric.WithDescription("Number of spans, log records or metric data points lost in failed exports"),
+		metric.WithUnit("{item}"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed")
+	}
+	dropped, err := meter.Int64ObservableCounter("otel.sdk.dropped",
+		metric.WithDescription("Number of spans or log records dropped because processor queue was full"),
+		metric.WithUnit("{item}"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "dropped")
+	}
+	queued, err := meter.Int64ObservableGauge("otel.sdk.queue.length",
+		metric.WithDescription("Number of spans or log records waiting in processor queue"),
+		metric.WithUnit("{item}"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "queue length")
+	}
+	capacity, err := meter.Int64ObservableGauge("otel.sdk.queue.capacity",
+		metric.WithDescription("Maximum number of spans or log records in processor queue"),
+		metric.WithUnit("{item}"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "queue capacity")
+	}
+	lastSuccess, err := meter.Float64ObservableGauge("otel.sdk.export.last_success",
+		metric.WithDescription("Unix time of last successful export"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "last success")
+	}
+
+	if _, err := meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		for _, e := range s.snapshot() {
+			attrs := metric.WithAttributeSet(e.attrs)
+			o.ObserveInt64(exports, e.exports.Load(), attrs)
+			o.ObserveInt64(failures, e.failures.Load(), attrs)
+			o.ObserveInt64(exported, e.exported.Load(), attrs)
+			o.ObserveInt64(failed, e.failed.Load(), attrs)
+			if c := e.capacity.Load(); c > 0 {
+				o.ObserveInt64(dropped, e.dropped.Load(), attrs)
+				o.ObserveInt64(queued, e.queued.Load(), attrs)
+				o.ObserveInt64(capacity, c, attrs)
+			}
+			if t := e.lastSuccess.Load(); t > 0 {
+				o.ObserveFloat64(lastSuccess, float64(t)/float64(time.Second), attrs)
+			}
+		}
+		return nil
+	}, exports, failures, exported, failed, dropped, queued, capacity, lastSuccess); err != nil {
+		return errors.Wrap(err, "register callback")
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package otelstats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-faster/errors"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type failingExporter struct{}
+
+func (failingExporter) ExportSpans(context.Context, []sdktrace.ReadOnlySpan) error {
+	return errors.New("unavailable")
+}
+
+func (failingExporter) Shutdown(context.Context) error { return nil }
+
+type logExporterFunc func(ctx context.Context, records []sdklog.Record) error
+
+func (f logExporterFunc) Export(ctx context.Context, records []sdklog.Record) error {
+	return f(ctx, records)
+}
+
+func (logExporterFunc) Shutdown(context.Context) error   { return nil }
+func (logExporterFunc) ForceFlush(context.Context) error { return nil }
+
+// collect returns value of each stats metric for signal and exporter.
+func collect(t *testing.T, r sdkmetric.Reader, signal, exporter string) map[string]float64 {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, r.Collect(context.Background(), &rm))
+
+	want := attribute.NewSet(
+		attribute.String("signal", signal),
+		attribute.String("exporter", exporter),
+	)
+	out := map[string]float64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					if dp.Attributes.Equals(&want) {
+						out[m.Name] = float64(dp.Value)
+					}
+				}
+			case metricdata.Gauge[int64]:
+				for _, dp := range data.DataPoints {
+					if dp.Attributes.Equals(&want) {
+						out[m.Name] = float64(dp.Value)
+					}
+				}
+			case metricdata.Gauge[float64]:
+				for _, dp := range data.DataPoints {
+					if dp.Attributes.Equals(&want) {
+						out[m.Name] = dp.Value
+					}
+				}
+			}
+		}
+	}
+	return out
+}
+
+func TestStats(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	reader := sdkmetric.NewManualReader()
+	require.NoError(t, s.Register(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+
+	t.Run("Dropped", func(t *testing.T) {
+		bsp := s.BatchSpanProcessor("failing", failingExporter{}, 4,
+			sdktrace.WithBatchTimeout(time.Hour),
+			sdktrace.WithMaxExportBatchSize(100),
+		)
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(bsp))
+		tracer := tp.Tracer("test")
+		for range 6 {
+			_, span := tracer.Start(ctx, "span")
+			span.End()
+		}
+		require.Equal(t, map[string]float64{
+			"otel.sdk.exports":         0,
+			"otel.sdk.export.failures": 0,
+			"otel.sdk.exported":        0,
+			"otel.sdk.export.failed":   0,
+			"otel.sdk.dropped":         2,
+			"otel.sdk.queue.length":    4,
+			"otel.sdk.queue.capacity":  4,
+		}, collect(t, reader, SignalTraces, "failing"))
+
+		require.NoError(t, tp.Shutdown(ctx))
+		require.Equal(t, map[string]float64{
+			"otel.sdk.exports":         1,
+			"otel.sdk.export.failures": 1,
+			"otel.sdk.exported":        0,
+			"otel.sdk.export.failed":   4,
+		}, collect(t, reader, SignalTraces, "failing"))
+	})
+	t.Run("Exported", func(t *testing.T) {
+		exp := tracetest.NewInMemoryExporter()
+		tp := sdktrace.NewTracerProvider(
+			sdktrace.WithSyncer(s.SpanExporter("memory", exp)),
+		)
+		_, span := tp.Tracer("test").Start(ctx, "span")
+		span.End()
+		require.Len(t, exp.GetSpans(), 1)
+
+		got := collect(t, reader, SignalTraces, "memory")
+		require.InDelta(t, float64(time.Now().Unix()), got["otel.sdk.export.last_success"], 5)
+		delete(got, "otel.sdk.export.last_success")
+		require.Equal(t, map[string]float64{
+			"otel.sdk.exports":         1,
+			"otel.sdk.export.failures": 0,
+			"otel.sdk.exported":        1,
+			"otel.sdk.export.failed":   0,
+		}, got)
+	})
+	t.Run("Logs", func(t *testing.T) {
+		exported := make(chan int, 1)
+		p := s.BatchLogProcessor("memory", logExporterFunc(func(_ context.Context, records []sdklog.Record) error {
+			if len(records) > 0 {
+				exported <- len(records)
+			}
+			return nil
+		}), 0)
+		lp := sdklog.NewLoggerProvider(sdklog.WithProcessor(p))
+		for range 3 {
+			var r sdklog.Record
+			require.NoError(t, p.OnEmit(ctx, &r))
+		}
+		require.NoError(t, lp.Shutdown(ctx))
+		require.Equal(t, 3, <-exported)
+
+		got := collect(t, reader, SignalLogs, "memory")
+		require.Equal(t, float64(3), got["otel.sdk.exported"])
+		require.NotContains(t, got, "otel.sdk.queue.length")
+	})
+	t.Run("Nil", func(t *testing.T) {
+		var nilStats *Stats
+		exp := tracetest.NewInMemoryExporter()
+		require.Same(t, exp, nilStats.SpanExporter("memory", exp))
+	})
+}
+
+func TestDataPoints(t *testing.T) {
+	rm := &metricdata.ResourceMetrics{
+		ScopeMetrics: []metricdata.ScopeMetrics{
+			{
+				Metrics: []metricdata.Metrics{
+					{Data: metricdata.Sum[int64]{DataPoints: make([]metricdata.DataPoint[int64], 2)}},
+					{Data: metricdata.Histogram[float64]{DataPoints: make([]metricdata.HistogramDataPoint[float64], 3)}},
+				},
+			},
+			{
+				Metrics: []metricdata.Metrics{
+					{Data: metricdata.Gauge[float64]{DataPoints: make([]metricdata.DataPoint[float64], 1)}},
+				},
+			},
+		},
+	}
+	require.Equal(t, 6, dataPoints(rm))
+}
@@ -0,0 +1,162 @@
+package tracez
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// bucketLabels returns human-readable labels of latency buckets.
+func bucketLabels() []string {
+	labels := make([]string, 0, len(LatencyBuckets)+1)
+	for _, b := range LatencyBuckets {
+		labels = append(labels, "<"+b.String())
+	}
+	return append(labels, ">="+LatencyBuckets[len(LatencyBuckets)-1].String())
+}
+
+var summaryTemplate = template.Must(template.New("summary").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Tracez</title></head>
+<body>
+<h1>Tracez</h1>
+<p><a href="?type=active">All active</a> | <a href="?type=errors">All errors</a></p>
+<table border="1" cellpadding="4">
+<tr><th>Name</th><th>Active</th>{{ range .Buckets }}<th>{{ . }}</th>{{ end }}<th>Errors</th></tr>
+{{- range .Spans }}
+{{- $name := .Name }}
+<tr>
+<td>{{ .Name }}</td>
+<td><a href="?type=active&name={{ .Name }}">{{ .Active }}</a></td>
+{{- range $i, $count := .Latency }}
+<td><a href="?type=latency&name={{ $name }}&bucket={{ $i }}">{{ $count }}</a></td>
+{{- end }}
+<td><a href="?type=errors&name={{ .Name }}">{{ .Errors }}</a></td>
+</tr>
+{{- end }}
+</table>
+</body>
+</html>
+`))
+
+var spansTemplate = template.Must(template.New("spans").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Tracez: {{ .Title }}</title></head>
+<body>
+<h1>{{ .Title }}</h1>
+<p><a href="?">Summary</a></p>
+<table border="1" cellpadding="4">
+<tr><th>Start</th><th>Duration</th><th>Name</th><th>Trace</th><th>Span</th><th>Parent</th><th>Kind</th><th>Status</th><th>Attributes</th><th>Events</th></tr>
+{{- range .Spans }}
+<tr>
+<td>{{ .Start.Format "2006-01-02T15:04:05.000000Z07:00" }}</td>
+<td>{{ .Duration }}</td>
+<td>{{ .Name }}</td>
+<td>{{ .TraceID }}</td>
+<td>{{ .SpanID }}</td>
+<td>{{ .ParentSpanID }}</td>
+<td>{{ .Kind }}</td>
+<td>{{ .Status }} {{ .Description }}</td>
+<td>{{ range $k, $v := .Attributes }}{{ $k }}={{ $v }}<br>{{ end }}</td>
+<td>{{ range .Events }}{{ .Time.Format "15:04:05.000000" }} {{ .Name }}{{ range $k, $v := .Attributes }} {{ $k }}={{ $v }}{{ end }}<br>{{ end }}</td>
+</tr>
+{{- end }}
+</table>
+</body>
+</html>
+`))
+
+type handler struct {
+	p *Processor
+}
+
+// NewHandler returns handler that renders spans recorded by p.
+//
+// Query parameters:
+//
+//   - type: "active", "latency" or "errors", summary is rendered if not set
+//   - name: span name, all spans for "active" and "errors" if not set
+//   - bucket: latency bucket index for "latency"
+//   - format: "json" to render JSON, also selected by Accept header
+func NewHandler(p *Processor) http.Handler {
+	return handler{p: p}
+}
+
+type summaryPayload struct {
+	Buckets []string  `json:"buckets"`
+	Spans   []Summary `json:"spans"`
+}
+
+type spansPayload struct {
+	Title string `json:"-"`
+	Spans []Span `json:"spans"`
+}
+
+type errorPayload struct {
+	Error string `json:"error"`
+}
+
+func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	asJSON := q.Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json")
+	fail := func(code int, msg string) {
+		if asJSON {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(code)
+			_ = json.NewEncoder(w).Encode(errorPayload{Error: msg})
+			return
+		}
+		http.Error(w, msg, code)
+	}
+	render := func(tpl *template.Template, v any) {
+		if asJSON {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(v)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = tpl.Execute(w, v)
+	}
+
+	name := q.Get("name")
+	switch typ := q.Get("type"); typ {
+	case "":
+		render(summaryTemplate, summaryPayload{
+			Buckets: bucketLabels(),
+			Spans:   h.p.Summary(),
+		})
+	case "active":
+		render(spansTemplate, spansPayload{
+			Title: title("Active", name),
+			Spans: h.p.Active(name),
+		})
+	case "errors":
+		render(spansTemplate, spansPayload{
+			Title: title("Errors", name),
+			Spans: h.p.Errors(name),
+		})
+	case "latency":
+		labels := bucketLabels()
+		bucket, err := strconv.Atoi(q.Get("bucket"))
+		if err != nil || bucket < 0 || bucket >= len(labels) {
+			fail(http.StatusBadRequest, fmt.Sprintf("invalid bucket %q", q.Get("bucket")))
+			return
+		}
+		render(spansTemplate, spansPayload{
+			Title: title("Latency "+labels[bucket], name),
+			Spans: h.p.Latency(name, bucket),
+		})
+	default:
+		fail(http.StatusBadRequest, fmt.Sprintf("unknown type %q", typ))
+	}
+}
+
+func title(prefix, name string) string {
+	if name == "" {
+		return prefix
+	}
+	return prefix + ": " + name
+}
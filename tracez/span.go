@@ -0,0 +1,80 @@
+package tracez
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Span is snapshot of recorded span.
+type Span struct {
+	Name         string            `json:"name"`
+	TraceID      string            `json:"trace_id"`
+	SpanID       string            `json:"span_id"`
+	ParentSpanID string            `json:"parent_span_id,omitempty"`
+	Kind         string            `json:"kind"`
+	Start        time.Time         `json:"start"`
+	End          time.Time         `json:"end,omitzero"`
+	Duration     time.Duration     `json:"duration"`
+	Status       string            `json:"status"`
+	Description  string            `json:"description,omitempty"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+	Events       []Event           `json:"events,omitempty"`
+}
+
+// Event is span event.
+type Event struct {
+	Name       string            `json:"name"`
+	Time       time.Time         `json:"time"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+func attributesMap(attrs []attribute.KeyValue) map[string]string {
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(attrs))
+	for _, kv := range attrs {
+		out[string(kv.Key)] = kv.Value.Emit()
+	}
+	return out
+}
+
+func newSpan(s sdktrace.ReadOnlySpan) Span {
+	out := Span{
+		Name:        s.Name(),
+		TraceID:     s.SpanContext().TraceID().String(),
+		SpanID:      s.SpanContext().SpanID().String(),
+		Kind:        s.SpanKind().String(),
+		Start:       s.StartTime(),
+		End:         s.EndTime(),
+		Status:      s.Status().Code.String(),
+		Description: s.Status().Description,
+		Attributes:  attributesMap(s.Attributes()),
+	}
+	if p := s.Parent(); p.HasSpanID() {
+		out.ParentSpanID = p.SpanID().String()
+	}
+	if out.End.IsZero() {
+		out.Duration = time.Since(out.Start)
+	} else {
+		out.Duration = out.End.Sub(out.Start)
+	}
+	for _, e := range s.Events() {
+		out.Events = append(out.Events, Event{
+			Name:       e.Name,
+			Time:       e.Time,
+			Attributes: attributesMap(e.Attributes),
+		})
+	}
+	return out
+}
+
+func newSpans(spans []sdktrace.ReadOnlySpan) []Span {
+	out := make([]Span, 0, len(spans))
+	for _, s := range spans {
+		out = append(out, newSpan(s))
+	}
+	return out
+}
@@ -0,0 +1,274 @@
+// Package tracez implements zPages-style in-process viewer of recent spans.
+//
+// [Processor] keeps in-flight spans, recent spans grouped by name and latency
+// bucket and recent errored spans in bounded buffers. [NewHandler] renders them
+// as HTML or JSON.
+package tracez
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LatencyBuckets are upper bounds of latency buckets, the last bucket is unbounded.
+var LatencyBuckets = []time.Duration{
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+	10 * time.Second,
+	100 * time.Second,
+}
+
+// latencyBucket returns index of latency bucket for d.
+func latencyBucket(d time.Duration) int {
+	return sort.Search(len(LatencyBuckets), func(i int) bool {
+		return d < LatencyBuckets[i]
+	})
+}
+
+// Options for [NewProcessor].
+type Options struct {
+	// BucketSize is maximum number of recent spans kept per span name and
+	// latency bucket. Defaults to 8.
+	BucketSize int
+	// ErrorsSize is maximum number of recent errored spans. Defaults to 64.
+	ErrorsSize int
+	// MaxNames is maximum number of tracked span names, spans with other names
+	// are ignored. Defaults to 256.
+	MaxNames int
+	// MaxActive is maximum number of tracked in-flight spans. Defaults to 1024.
+	MaxActive int
+}
+
+func (o *Options) setDefaults() {
+	if o.BucketSize <= 0 {
+		o.BucketSize = 8
+	}
+	if o.ErrorsSize <= 0 {
+		o.ErrorsSize = 64
+	}
+	if o.MaxNames <= 0 {
+		o.MaxNames = 256
+	}
+	if o.MaxActive <= 0 {
+		o.MaxActive = 1024
+	}
+}
+
+// ring is fixed-size buffer that overwrites oldest elements.
+type ring[T any] struct {
+	buf  []T
+	next int
+	full bool
+}
+
+func newRing[T any](size int) ring[T] {
+	return ring[T]{buf: make([]T, size)}
+}
+
+func (r *ring[T]) push(v T) {
+	r.buf[r.next] = v
+	r.next++
+	if r.next == len(r.buf) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// items returns elements from newest to oldest.
+func (r *ring[T]) items() []T {
+	n := r.next
+	if r.full {
+		n = len(r.buf)
+	}
+	out := make([]T, 0, n)
+	for i := 1; i <= n; i++ {
+		out = append(out, r.buf[(r.next-i+len(r.buf))%len(r.buf)])
+	}
+	return out
+}
+
+type bucket struct {
+	count uint64
+	spans ring[sdktrace.ReadOnlySpan]
+}
+
+type spanGroup struct {
+	buckets []bucket
+	errors  uint64
+}
+
+type activeSpan struct {
+	name string
+	span sdktrace.ReadOnlySpan
+}
+
+// Processor is [sdktrace.SpanProcessor] that records spans for viewing.
+//
+// Shutdown and ForceFlush are no-op, so Processor can be shared between
+// tracer providers, keeping recorded spans.
+type Processor struct {
+	opts Options
+
+	mux    sync.Mutex
+	active map[trace.SpanID]activeSpan
+	groups map[string]*spanGroup
+	errors ring[sdktrace.ReadOnlySpan]
+}
+
+var _ sdktrace.SpanProcessor = (*Processor)(nil)
+
+// NewProcessor creates new Processor.
+func NewProcessor(opts Options) *Processor {
+	opts.setDefaults()
+	return &Processor{
+		opts:   opts,
+		active: map[trace.SpanID]activeSpan{},
+		groups: map[string]*spanGroup{},
+		errors: newRing[sdktrace.ReadOnlySpan](opts.ErrorsSize),
+	}
+}
+
+// group returns group for span name or nil if names limit is reached.
+func (p *Processor) group(name string) *spanGroup {
+	if g, ok := p.groups[name]; ok {
+		return g
+	}
+	if len(p.groups) >= p.opts.MaxNames {
+		return nil
+	}
+	g := &spanGroup{
+		buckets: make([]bucket, len(LatencyBuckets)+1),
+	}
+	for i := range g.buckets {
+		g.buckets[i].spans = newRing[sdktrace.ReadOnlySpan](p.opts.BucketSize)
+	}
+	p.groups[name] = g
+	return g
+}
+
+// OnStart implements [sdktrace.SpanProcessor].
+func (p *Processor) OnStart(_ context.Context, s sdktrace.ReadWriteSpan) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if len(p.active) >= p.opts.MaxActive || p.group(s.Name()) == nil {
+		return
+	}
+	p.active[s.SpanContext().SpanID()] = activeSpan{name: s.Name(), span: s}
+}
+
+// OnEnd implements [sdktrace.SpanProcessor].
+func (p *Processor) OnEnd(s sdktrace.ReadOnlySpan) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	delete(p.active, s.SpanContext().SpanID())
+	g := p.group(s.Name())
+	if g == nil {
+		return
+	}
+	b := &g.buckets[latencyBucket(s.EndTime().Sub(s.StartTime()))]
+	b.count++
+	b.spans.push(s)
+	if s.Status().Code == codes.Error {
+		g.errors++
+		p.errors.push(s)
+	}
+}
+
+// Shutdown implements [sdktrace.SpanProcessor]. It is no-op.
+func (p *Processor) Shutdown(context.Context) error { return nil }
+
+// ForceFlush implements [sdktrace.SpanProcessor]. It is no-op.
+func (p *Processor) ForceFlush(context.Context) error { return nil }
+
+// Summary is span statistics for span name.
+type Summary struct {
+	Name string `json:"name"`
+	// Active is number of tracked in-flight spans.
+	Active int `json:"active"`
+	// Latency is number of ended spans per latency bucket, see [LatencyBuckets].
+	Latency []uint64 `json:"latency"`
+	Errors  uint64   `json:"errors"`
+}
+
+// Summary returns statistics for all span names, sorted by name.
+func (p *Processor) Summary() []Summary {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	active := map[string]int{}
+	for _, s := range p.active {
+		active[s.name]++
+	}
+	out := make([]Summary, 0, len(p.groups))
+	for name, g := range p.groups {
+		s := Summary{
+			Name:    name,
+			Active:  active[name],
+			Latency: make([]uint64, len(g.buckets)),
+			Errors:  g.errors,
+		}
+		for i, b := range g.buckets {
+			s.Latency[i] = b.count
+		}
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// Active returns in-flight spans with given name, or all if name is empty.
+func (p *Processor) Active(name string) []Span {
+	p.mux.Lock()
+	var spans []sdktrace.ReadOnlySpan
+	for _, s := range p.active {
+		if name == "" || s.name == name {
+			spans = append(spans, s.span)
+		}
+	}
+	p.mux.Unlock()
+
+	out := make([]Span, 0, len(spans))
+	for _, s := range spans {
+		out = append(out, newSpan(s))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Start.After(out[j].Start)
+	})
+	return out
+}
+
+// Latency returns recent spans with given name in latency bucket, newest first.
+func (p *Processor) Latency(name string, bucket int) []Span {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	g, ok := p.groups[name]
+	if !ok || bucket < 0 || bucket >= len(g.buckets) {
+		return nil
+	}
+	return newSpans(g.buckets[bucket].spans.items())
+}
+
+// Errors returns recent errored spans with given name, or all if name is empty,
+// newest first.
+func (p *Processor) Errors(name string) []Span {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	var spans []sdktrace.ReadOnlySpan
+	for _, s := range p.errors.items() {
+		if name == "" || s.Name() == name {
+			spans = append(spans, s)
+		}
+	}
+	return newSpans(spans)
+}
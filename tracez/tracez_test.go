@@ -0,0 +1,129 @@
+package tracez
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestLatencyBucket(t *testing.T) {
+	require.Equal(t, 0, latencyBucket(0))
+	require.Equal(t, 0, latencyBucket(9*time.Microsecond))
+	require.Equal(t, 1, latencyBucket(10*time.Microsecond))
+	require.Equal(t, 3, latencyBucket(5*time.Millisecond))
+	require.Equal(t, len(LatencyBuckets), latencyBucket(time.Hour))
+}
+
+func TestRing(t *testing.T) {
+	r := newRing[int](3)
+	require.Empty(t, r.items())
+	r.push(1)
+	r.push(2)
+	require.Equal(t, []int{2, 1}, r.items())
+	r.push(3)
+	r.push(4)
+	require.Equal(t, []int{4, 3, 2}, r.items())
+}
+
+func TestProcessor(t *testing.T) {
+	ctx := context.Background()
+	p := NewProcessor(Options{
+		BucketSize: 2,
+		ErrorsSize: 2,
+		MaxNames:   3,
+	})
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(p)).Tracer("test")
+	spanWithDuration := func(name string, d time.Duration) trace.Span {
+		start := time.Now()
+		_, span := tracer.Start(ctx, name, trace.WithTimestamp(start))
+		span.End(trace.WithTimestamp(start.Add(d)))
+		return span
+	}
+
+	_, active := tracer.Start(ctx, "active")
+	for range 3 {
+		spanWithDuration("fast", time.Microsecond)
+	}
+	spanWithDuration("slow", 2*time.Second)
+	_, failed := tracer.Start(ctx, "slow")
+	failed.SetStatus(codes.Error, "failed")
+	failed.End()
+	// Names limit reached.
+	spanWithDuration("ignored", time.Second)
+
+	summary := p.Summary()
+	require.Len(t, summary, 3)
+	require.Equal(t, "active", summary[0].Name)
+	require.Equal(t, 1, summary[0].Active)
+	require.Equal(t, "fast", summary[1].Name)
+	require.Equal(t, uint64(3), summary[1].Latency[0])
+	require.Equal(t, "slow", summary[2].Name)
+	require.Equal(t, uint64(1), summary[2].Latency[6])
+	require.Equal(t, uint64(1), summary[2].Errors)
+
+	require.Len(t, p.Active(""), 1)
+	require.Empty(t, p.Active("fast"))
+	require.Len(t, p.Latency("fast", 0), 2, "bucket size")
+	require.Empty(t, p.Latency("unknown", 0))
+	require.Empty(t, p.Latency("fast", 100))
+
+	errored := p.Errors("")
+	require.Len(t, errored, 1)
+	require.Equal(t, "slow", errored[0].Name)
+	require.Equal(t, "Error", errored[0].Status)
+	require.Equal(t, "failed", errored[0].Description)
+
+	active.End()
+	require.Empty(t, p.Active(""))
+	require.NoError(t, p.Shutdown(ctx))
+	require.Len(t, p.Summary(), 3, "shutdown should keep spans")
+}
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	p := NewProcessor(Options{})
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(p)).Tracer("test")
+	now := time.Now()
+	_, span := tracer.Start(ctx, "request", trace.WithTimestamp(now))
+	span.SetStatus(codes.Error, "<failed>")
+	span.End(trace.WithTimestamp(now))
+
+	h := NewHandler(p)
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	rec := get("/debug/tracez")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), "request")
+
+	rec = get("/debug/tracez?type=errors&name=request")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), "&lt;failed&gt;")
+
+	rec = get("/debug/tracez?format=json")
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var summary summaryPayload
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &summary))
+	require.Len(t, summary.Buckets, len(LatencyBuckets)+1)
+	require.Equal(t, "request", summary.Spans[0].Name)
+
+	rec = get("/debug/tracez?format=json&type=latency&name=request&bucket=0")
+	var spans spansPayload
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &spans))
+	require.Len(t, spans.Spans, 1)
+	require.Equal(t, span.SpanContext().TraceID().String(), spans.Spans[0].TraceID)
+
+	require.Equal(t, http.StatusBadRequest, get("/debug/tracez?type=latency&bucket=100").Code)
+	require.Equal(t, http.StatusBadRequest, get("/debug/tracez?type=unknown").Code)
+}